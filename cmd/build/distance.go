@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/gracefulearth/gebco"
+	"github.com/gracefulearth/gopixi"
+)
+
+// appendCoastDistanceLayer computes the distance from every pixel to the nearest land and ocean over a global
+// land mask decimated from the high resolution layer, and appends them to the Pixi file as a layer of size tiles
+// per GEBCO tile in each direction. A decimated pixel is a site of land if any of the pixels it covers is land, and a
+// site of ocean if any is ocean, so that islands and straits narrower than it still count; a coastal pixel is
+// therefore at zero distance from both. The high resolution layer is read tile by tile in parallel so that each of
+// its tiles is only decoded once.
+func appendCoastDistanceLayer(pixiFile *os.File, summary *gopixi.Pixi, highRes gopixi.TileAccessLayer, size int, opts []gopixi.LayerOption) error {
+	grid, err := gebco.NewGrid(highRes)
+	if err != nil {
+		return err
+	}
+	region, err := grid.Region(gebco.GlobalBounds)
+	if err != nil {
+		return err
+	}
+
+	factor := gebco.GtiffTileSize / size
+	width, height := size*gebco.TilesX, size*gebco.TilesY
+	anyLand, anyOcean := make([]bool, width*height), make([]bool, width*height)
+	var merge sync.Mutex
+	err = region.EachTile(0, func(_ int, part gebco.GridRegion, samples []gebco.GebcoSample) error {
+		// classify the decimated pixels the part overlaps on their own, as they may straddle parts on other workers
+		x0, y0 := part.X/factor, part.Y/factor
+		blocksX, blocksY := (part.X+part.Width-1)/factor-x0+1, (part.Y+part.Height-1)/factor-y0+1
+		land, ocean := make([]bool, blocksX*blocksY), make([]bool, blocksX*blocksY)
+		for j := range part.Height {
+			for i := range part.Width {
+				block := ((part.Y+j)/factor-y0)*blocksX + (part.X+i)/factor - x0
+				if samples[part.Index(i, j)].IsLand() {
+					land[block] = true
+				} else {
+					ocean[block] = true
+				}
+			}
+		}
+		merge.Lock()
+		defer merge.Unlock()
+		for by := range blocksY {
+			for bx := range blocksX {
+				index := (y0+by)*width + x0 + bx
+				anyLand[index] = anyLand[index] || land[by*blocksX+bx]
+				anyOcean[index] = anyOcean[index] || ocean[by*blocksX+bx]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to classify land: %w", err)
+	}
+
+	fmt.Println("Computing distances to land...")
+	toLand := gebco.DistanceTransform(anyLand, width)
+	fmt.Println("Computing distances to ocean...")
+	toOcean := gebco.DistanceTransform(anyOcean, width)
+	distanceLayer := gopixi.NewLayer("gebco_coast_distance",
+		gopixi.DimensionSet{
+			{Name: "lng", TileSize: size, Size: width},
+			{Name: "lat", TileSize: size, Size: height}},
+		gopixi.ChannelSet{
+			{Name: "to-land", Type: gopixi.ChannelFloat32},
+			{Name: "to-ocean", Type: gopixi.ChannelFloat32}},
+		opts...,
+	)
+
+	distanceIterator := gopixi.NewTileOrderWriteIterator(pixiFile, summary.Header, distanceLayer)
+	return summary.AppendIterativeLayer(pixiFile, distanceLayer, distanceIterator, func(dstIterator gopixi.IterativeLayerWriter) error {
+		for dstIterator.Next() {
+			coord := dstIterator.Coordinate()
+			index := coord[1]*width + coord[0]
+			if math.IsInf(float64(toLand[index]), 1) || math.IsInf(float64(toOcean[index]), 1) {
+				return fmt.Errorf("no land or no ocean in the land mask")
+			}
+			dstIterator.SetSample(gopixi.Sample{toLand[index], toOcean[index]})
+		}
+		return nil
+	})
+}
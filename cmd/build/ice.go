@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gracefulearth/gebco"
+	"github.com/gracefulearth/gopixi"
+)
+
+// appendIceLayer derives the ice thickness and ice class of every pixel of the high resolution layer and appends
+// them to the Pixi file as a layer with the same dimensions.
+func appendIceLayer(pixiFile *os.File, summary *gopixi.Pixi, highRes gopixi.TileAccessLayer, opts []gopixi.LayerOption) error {
+	grid, err := gebco.NewGrid(highRes)
+	if err != nil {
+		return err
+	}
+
+	iceLayer := gopixi.NewLayer(gebco.IceLayerName,
+		highRes.Layer().Dimensions,
+		gopixi.ChannelSet{
+			{Name: "ice-thickness", Type: gopixi.ChannelInt16},
+			{Name: "ice-class", Type: gopixi.ChannelUint8}},
+		opts...,
+	)
+
+	iceIterator := gopixi.NewTileOrderWriteIterator(pixiFile, summary.Header, iceLayer)
+	return summary.AppendIterativeLayer(pixiFile, iceLayer, iceIterator, func(dstIterator gopixi.IterativeLayerWriter) error {
+		processed := 0
+		for dstIterator.Next() {
+			coord := dstIterator.Coordinate()
+			sample, err := grid.Sample(coord[0], coord[1])
+			if err != nil {
+				return fmt.Errorf("failed to classify ice at coordinate %v: %w", coord, err)
+			}
+			ice := sample.IceGeometry()
+			dstIterator.SetSample(gopixi.Sample{int16(ice.Thickness), uint8(ice.Class)})
+
+			processed += 1
+			if processed%(gebco.TotalPixels/16) == 0 {
+				fmt.Println("Ice pixels processed:", processed, "/", gebco.TotalPixels)
+			}
+		}
+		return nil
+	})
+}
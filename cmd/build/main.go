@@ -8,6 +8,7 @@ import (
 	"image/color"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/gracefulearth/gebco"
 	"github.com/gracefulearth/go-colorext"
@@ -23,6 +24,18 @@ func main() {
 	planarArg := flag.Bool("planar", false, "whether to use planar (separated) or interleaved channel storage in the Pixi file")
 	orderArg := flag.String("endian", "native", "the endianness byte order (big, little, native) to use in the Pixi file")
 	overviewSizeArg := flag.Int("overviewSize", gebco.GtiffTileSize/10, "the size of the overview layer tiles to generate in the Pixi file")
+	terrainArg := flag.Bool("terrain", false, "whether to add a layer of slope, aspect and curvature derived from the high resolution layer")
+	terrainSurfaceArg := gebco.GebcoDataIce
+	flag.TextVar(&terrainSurfaceArg, "terrainSurface", gebco.GebcoDataIce, "the surface (ice, sub-ice) to derive the terrain and roughness layers from")
+	roughnessArg := flag.Bool("roughness", false, "whether to add a layer of rugosity, TRI, VRM and multi-scale BPI derived from the high resolution layer")
+	roughnessVrmRadiusArg := flag.Float64("roughnessVrmRadius", 1000, "the radius in metres of the neighbourhood of the roughness layer VRM")
+	roughnessBpiArg := flag.String("roughnessBpi", "1000:5000,5000:25000", "comma separated inner:outer radii in metres of the roughness layer BPI annuli")
+	landMaskArg := flag.Bool("landMask", false, "whether to add a boolean land/sea mask layer derived from the high resolution layer")
+	coastDistanceArg := flag.Bool("coastDistance", false, "whether to add a layer of geodesic distances in metres to the nearest land and ocean")
+	coastDistanceSizeArg := flag.Int("coastDistanceSize", gebco.GtiffTileSize/10, "the size of the coast distance layer tiles, one per GEBCO tile, setting its resolution: islands and straits narrower than a pixel still count, but distances are between pixel centres (must be a divisor of GEBCO tile size = 21600, which is full resolution); the layer has 8*size*size pixels and computing it takes about 26 bytes of memory per pixel, 1 GB at the default size and 97 GB at full resolution")
+	iceArg := flag.Bool("ice", false, "whether to add a layer of ice thickness and ice class (open water, grounded, floating, bare land) derived from the high resolution layer")
+	yearsArg := flag.String("years", "", "comma separated GEBCO years (e.g. 2021,2022,2023,2024,2025) to stack into an additional layer with a year dimension")
+	shoalOverviewSizesArg := flag.String("shoalOverviewSizes", "", "comma separated sizes of overview layer tiles (e.g. 2160,270) holding the shoalest depth of each pixel, for draught routing (each must be a divisor of GEBCO tile size = 21600)")
 	flag.Parse()
 
 	// validate arguments
@@ -36,6 +49,40 @@ func main() {
 		return
 	}
 
+	if *coastDistanceSizeArg <= 0 || *coastDistanceSizeArg > gebco.GtiffTileSize || (gebco.GtiffTileSize%*coastDistanceSizeArg) != 0 {
+		fmt.Printf("invalid coast distance size argument: %d\n", *coastDistanceSizeArg)
+		return
+	}
+
+	years, err := gebco.ParseYears(*yearsArg)
+	if err != nil {
+		fmt.Printf("invalid years argument: %v\n", err)
+		return
+	}
+
+	shoalOverviewSizes := []int{}
+	if *shoalOverviewSizesArg != "" {
+		for _, part := range strings.Split(*shoalOverviewSizesArg, ",") {
+			size, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || size <= 0 || size > gebco.GtiffTileSize || (gebco.GtiffTileSize%size) != 0 {
+				fmt.Printf("invalid shoal overview size argument: %s\n", part)
+				return
+			}
+			shoalOverviewSizes = append(shoalOverviewSizes, size)
+		}
+	}
+
+	bpiAnnuli, err := gebco.ParseAnnuli(*roughnessBpiArg)
+	if err != nil {
+		fmt.Printf("invalid roughness BPI argument: %v\n", err)
+		return
+	}
+
+	if terrainSurfaceArg == gebco.GebcoDataTypeId {
+		fmt.Printf("invalid terrain surface argument: tid\n")
+		return
+	}
+
 	compression := gopixi.CompressionNone
 	switch *compressionArg {
 	case 0:
@@ -70,6 +117,12 @@ func main() {
 	allGebcoFiles := gebco.GebcoLayeredTiles(*yearArg)
 
 	missing := gebco.CheckDirectoryComplete(*srcArg, allGebcoFiles)
+	for _, year := range years {
+		if year == *yearArg {
+			continue
+		}
+		missing = append(missing, gebco.CheckDirectoryComplete(*srcArg, gebco.GebcoLayeredTiles(year))...)
+	}
 	if len(missing) > 0 {
 		fmt.Printf("missing %d GEBCO files:\n", len(missing))
 		for _, miss := range missing {
@@ -94,7 +147,11 @@ func main() {
 		return
 	}
 
-	err = summary.AppendTags(pixiFile, map[string]string{"year": strconv.Itoa(*yearArg)})
+	tags := map[string]string{"year": strconv.Itoa(*yearArg)}
+	if len(years) > 0 {
+		tags["years"] = gebco.FormatYears(years)
+	}
+	err = summary.AppendTags(pixiFile, tags)
 	if err != nil {
 		fmt.Printf("failed to write Pixi tags: %v\n", err)
 		return
@@ -224,4 +281,84 @@ func main() {
 		fmt.Printf("failed to write Pixi overview layer: %v\n", err)
 		return
 	}
+
+	// add the terrain layer
+	if *terrainArg {
+		fmt.Println("Generating terrain layer...")
+		terrainCache := gopixi.NewFifoCacheReadLayer(readFile, summary.Header, highResLayer, 16)
+		err = appendTerrainLayer(pixiFile, summary, terrainCache, terrainSurfaceArg, opts)
+		if err != nil {
+			fmt.Printf("failed to write Pixi terrain layer: %v\n", err)
+			return
+		}
+	}
+
+	// add the roughness layer
+	if *roughnessArg {
+		fmt.Println("Generating roughness layer...")
+		roughnessCache := gopixi.NewFifoCacheReadLayer(readFile, summary.Header, highResLayer, 64)
+		err = appendRoughnessLayer(pixiFile, summary, roughnessCache, gebco.RoughnessOptions{
+			Surface:   terrainSurfaceArg,
+			VrmRadius: *roughnessVrmRadiusArg,
+			Bpi:       bpiAnnuli,
+		}, opts)
+		if err != nil {
+			fmt.Printf("failed to write Pixi roughness layer: %v\n", err)
+			return
+		}
+	}
+
+	// add the land mask layer
+	if *landMaskArg {
+		fmt.Println("Generating land mask layer...")
+		maskCache := gopixi.NewFifoCacheReadLayer(readFile, summary.Header, highResLayer, 16)
+		err = appendLandMaskLayer(pixiFile, summary, maskCache, opts)
+		if err != nil {
+			fmt.Printf("failed to write Pixi land mask layer: %v\n", err)
+			return
+		}
+	}
+
+	// add the coast distance layer
+	if *coastDistanceArg {
+		fmt.Println("Generating coast distance layer...")
+		distanceCache := gopixi.NewFifoCacheReadLayer(readFile, summary.Header, highResLayer, 16)
+		err = appendCoastDistanceLayer(pixiFile, summary, distanceCache, *coastDistanceSizeArg, opts)
+		if err != nil {
+			fmt.Printf("failed to write Pixi coast distance layer: %v\n", err)
+			return
+		}
+	}
+
+	// add the ice layer
+	if *iceArg {
+		fmt.Println("Generating ice layer...")
+		iceCache := gopixi.NewFifoCacheReadLayer(readFile, summary.Header, highResLayer, 16)
+		err = appendIceLayer(pixiFile, summary, iceCache, opts)
+		if err != nil {
+			fmt.Printf("failed to write Pixi ice layer: %v\n", err)
+			return
+		}
+	}
+
+	// add the stacked years layer
+	if len(years) > 0 {
+		fmt.Println("Generating stacked years layer...")
+		err = appendYearsLayer(pixiFile, summary, *srcArg, years, highResLayer, opts)
+		if err != nil {
+			fmt.Printf("failed to write Pixi years layer: %v\n", err)
+			return
+		}
+	}
+
+	// add the shoal overview layers
+	for _, size := range shoalOverviewSizes {
+		fmt.Println("Generating shoal overview layer of size", size, "...")
+		shoalCache := gopixi.NewFifoCacheReadLayer(readFile, summary.Header, highResLayer, 16)
+		err = appendShoalOverviewLayer(pixiFile, summary, shoalCache, size, opts)
+		if err != nil {
+			fmt.Printf("failed to write Pixi shoal overview layer: %v\n", err)
+			return
+		}
+	}
 }
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gracefulearth/gebco"
+	"github.com/gracefulearth/gopixi"
+)
+
+// appendLandMaskLayer classifies every pixel of the high resolution layer as land or sea and appends the result
+// to the Pixi file as a boolean layer with the same dimensions.
+func appendLandMaskLayer(pixiFile *os.File, summary *gopixi.Pixi, highRes gopixi.TileAccessLayer, opts []gopixi.LayerOption) error {
+	grid, err := gebco.NewGrid(highRes)
+	if err != nil {
+		return err
+	}
+
+	maskLayer := gopixi.NewLayer("gebco_land_mask",
+		highRes.Layer().Dimensions,
+		gopixi.ChannelSet{{Name: "land", Type: gopixi.ChannelBool}},
+		opts...,
+	)
+
+	maskIterator := gopixi.NewTileOrderWriteIterator(pixiFile, summary.Header, maskLayer)
+	return summary.AppendIterativeLayer(pixiFile, maskLayer, maskIterator, func(dstIterator gopixi.IterativeLayerWriter) error {
+		processed := 0
+		for dstIterator.Next() {
+			coord := dstIterator.Coordinate()
+			sample, err := grid.Sample(coord[0], coord[1])
+			if err != nil {
+				return fmt.Errorf("failed to classify land at coordinate %v: %w", coord, err)
+			}
+			dstIterator.SetSample(gopixi.Sample{sample.IsLand()})
+
+			processed += 1
+			if processed%(gebco.TotalPixels/16) == 0 {
+				fmt.Println("Land mask pixels processed:", processed, "/", gebco.TotalPixels)
+			}
+		}
+		return nil
+	})
+}
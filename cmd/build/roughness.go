@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gracefulearth/gebco"
+	"github.com/gracefulearth/gopixi"
+)
+
+// appendRoughnessLayer computes rugosity, TRI, VRM and the bathymetric position index at each scale from the high
+// resolution layer and appends them to the Pixi file as a layer with the same dimensions. Each tile is computed as a
+// whole when the iterator first reaches it, reading the rows of the surface it needs once.
+func appendRoughnessLayer(pixiFile *os.File, summary *gopixi.Pixi, highRes gopixi.TileAccessLayer, roughnessOpts gebco.RoughnessOptions, opts []gopixi.LayerOption) error {
+	grid, err := gebco.NewGrid(highRes)
+	if err != nil {
+		return err
+	}
+	kernel, err := gebco.NewRoughnessKernel(grid, roughnessOpts)
+	if err != nil {
+		return err
+	}
+
+	channels := gopixi.ChannelSet{}
+	for _, name := range kernel.BandNames() {
+		channels = append(channels, gopixi.Channel{Name: name, Type: gopixi.ChannelFloat32})
+	}
+	roughnessLayer := gopixi.NewLayer("gebco_roughness", highRes.Layer().Dimensions, channels, opts...)
+
+	roughnessIterator := gopixi.NewTileOrderWriteIterator(pixiFile, summary.Header, roughnessLayer)
+	return summary.AppendIterativeLayer(pixiFile, roughnessLayer, roughnessIterator, func(dstIterator gopixi.IterativeLayerWriter) error {
+		processed := 0
+		sample := make(gopixi.Sample, len(channels))
+		dimensions := highRes.Layer().Dimensions
+		tileWidth, tileHeight := dimensions[0].TileSize, dimensions[1].TileSize
+		var tile gebco.GridRegion
+		var raster *gebco.Raster
+		for dstIterator.Next() {
+			coord := dstIterator.Coordinate()
+			if raster == nil || coord[0] < tile.X || coord[0] >= tile.X+tile.Width || coord[1] < tile.Y || coord[1] >= tile.Y+tile.Height {
+				x, y := coord[0]/tileWidth*tileWidth, coord[1]/tileHeight*tileHeight
+				tile = gebco.GridRegion{Grid: grid, X: x, Y: y, Width: min(tileWidth, grid.Width()-x), Height: min(tileHeight, grid.Height()-y)}
+				raster, err = kernel.Raster(tile)
+				if err != nil {
+					return fmt.Errorf("failed to compute roughness of tile at coordinate %v: %w", coord, err)
+				}
+			}
+			for i := range sample {
+				sample[i] = raster.Bands[i][tile.Index(coord[0]-tile.X, coord[1]-tile.Y)]
+			}
+			dstIterator.SetSample(sample)
+
+			processed += 1
+			if processed%(gebco.TotalPixels/16) == 0 {
+				fmt.Println("Roughness pixels processed:", processed, "/", gebco.TotalPixels)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/gracefulearth/gebco"
+	"github.com/gracefulearth/gopixi"
+)
+
+// appendShoalOverviewLayer appends an overview layer of the given tile size per GEBCO tile holding the highest
+// ice and sub-ice elevations, and so the shoalest depths, of the high resolution pixels each of its pixels covers.
+// Unlike the averaged overview it never hides a shoal, so routing over it errs on the side of caution.
+func appendShoalOverviewLayer(pixiFile *os.File, summary *gopixi.Pixi, highRes gopixi.TileAccessLayer, size int, opts []gopixi.LayerOption) error {
+	shoalLayer := gopixi.NewLayer(fmt.Sprintf("%s_%d", gebco.ShoalLayerPrefix, size),
+		gopixi.DimensionSet{
+			{Name: "lng", TileSize: size, Size: size * gebco.TilesX},
+			{Name: "lat", TileSize: size, Size: size * gebco.TilesY}},
+		gopixi.ChannelSet{
+			{Name: "ice", Type: gopixi.ChannelInt16},
+			{Name: "sub-ice", Type: gopixi.ChannelInt16}},
+		opts...,
+	)
+
+	factor := gebco.GtiffTileSize / size
+	sample := make(gopixi.Sample, len(highRes.Layer().Channels))
+	shoalIterator := gopixi.NewTileOrderWriteIterator(pixiFile, summary.Header, shoalLayer)
+	return summary.AppendIterativeLayer(pixiFile, shoalLayer, shoalIterator, func(dstIterator gopixi.IterativeLayerWriter) error {
+		for dstIterator.Next() {
+			coord := dstIterator.Coordinate()
+
+			shoalIce := int16(math.MinInt16)
+			shoalSubIce := int16(math.MinInt16)
+			for y := coord[1] * factor; y < (coord[1]+1)*factor; y++ {
+				for x := coord[0] * factor; x < (coord[0]+1)*factor; x++ {
+					err := gopixi.SampleInto(highRes, []int{x, y}, sample)
+					if err != nil {
+						return fmt.Errorf("failed to read sample at coordinate %v: %w", []int{x, y}, err)
+					}
+					shoalIce = max(shoalIce, sample[0].(int16))
+					shoalSubIce = max(shoalSubIce, sample[1].(int16))
+				}
+			}
+
+			dstIterator.SetSample(gopixi.Sample{shoalIce, shoalSubIce})
+		}
+		return nil
+	})
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gracefulearth/gebco"
+	"github.com/gracefulearth/gopixi"
+)
+
+// appendTerrainLayer derives slope, aspect and curvature from the high resolution layer and appends them to the
+// Pixi file as a layer with the same dimensions. Neighbourhoods are read through the grid so tile edges, the
+// antimeridian and the poles are handled without seams.
+func appendTerrainLayer(pixiFile *os.File, summary *gopixi.Pixi, highRes gopixi.TileAccessLayer, surface gebco.GebcoDataType, opts []gopixi.LayerOption) error {
+	grid, err := gebco.NewGrid(highRes)
+	if err != nil {
+		return err
+	}
+
+	terrainLayer := gopixi.NewLayer("gebco_terrain",
+		highRes.Layer().Dimensions,
+		gopixi.ChannelSet{
+			{Name: "slope", Type: gopixi.ChannelFloat32},
+			{Name: "aspect", Type: gopixi.ChannelFloat32},
+			{Name: "profile-curvature", Type: gopixi.ChannelFloat32},
+			{Name: "plan-curvature", Type: gopixi.ChannelFloat32}},
+		opts...,
+	)
+
+	terrainIterator := gopixi.NewTileOrderWriteIterator(pixiFile, summary.Header, terrainLayer)
+	return summary.AppendIterativeLayer(pixiFile, terrainLayer, terrainIterator, func(dstIterator gopixi.IterativeLayerWriter) error {
+		processed := 0
+		for dstIterator.Next() {
+			coord := dstIterator.Coordinate()
+			terrain, err := grid.Terrain(coord[0], coord[1], surface)
+			if err != nil {
+				return fmt.Errorf("failed to compute terrain at coordinate %v: %w", coord, err)
+			}
+			dstIterator.SetSample(gopixi.Sample{
+				float32(terrain.Slope),
+				float32(terrain.Aspect),
+				float32(terrain.ProfileCurvature),
+				float32(terrain.PlanCurvature),
+			})
+
+			processed += 1
+			if processed%(gebco.TotalPixels/16) == 0 {
+				fmt.Println("Terrain pixels processed:", processed, "/", gebco.TotalPixels)
+			}
+		}
+		return nil
+	})
+}
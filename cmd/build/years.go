@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+
+	"github.com/gracefulearth/gebco"
+	"github.com/gracefulearth/go-colorext"
+	"github.com/gracefulearth/gopixi"
+)
+
+// appendYearsLayer stacks the GEBCO tiff files of several releases into a layer with the same lng and lat
+// dimensions as the high resolution layer and a third year dimension, one year per tile along it, so that a
+// single set of GEBCO tiles is loaded at a time.
+func appendYearsLayer(pixiFile *os.File, summary *gopixi.Pixi, srcDir string, years []int, highRes gopixi.Layer, opts []gopixi.LayerOption) error {
+	allGebcoFiles := make([][]gebco.GebcoTifLayer, len(years))
+	for i, year := range years {
+		allGebcoFiles[i] = gebco.GebcoLayeredTiles(year)
+	}
+
+	yearsLayer := gopixi.NewLayer("gebco_years",
+		gopixi.DimensionSet{
+			highRes.Dimensions[0],
+			highRes.Dimensions[1],
+			{Name: "year", TileSize: 1, Size: len(years)}},
+		gopixi.ChannelSet{
+			{Name: "ice", Type: gopixi.ChannelInt16},
+			{Name: "sub-ice", Type: gopixi.ChannelInt16},
+			{Name: "tid", Type: gopixi.ChannelUint8}},
+		opts...,
+	)
+
+	gebcoTileTracker := -1
+	var gebcoIceTile image.Image
+	var gebcoSubIceTile image.Image
+	var gebcoTidTile image.Image
+
+	iterator := gebco.NewGebcoTileOrderWriteIterator(pixiFile, summary.Header, yearsLayer)
+	return summary.AppendIterativeLayer(pixiFile, yearsLayer, iterator, func(dstIterator gopixi.IterativeLayerWriter) error {
+		for dstIterator.Next() {
+			coord := dstIterator.Coordinate()
+			gebcoTile := coord[0]/gebco.GtiffTileSize + (coord[1]/gebco.GtiffTileSize)*gebco.TilesX
+			xGebcoTile := gebcoTile % gebco.TilesX
+			yGebcoTile := gebcoTile / gebco.TilesX
+
+			if yearTile := gebcoTile + coord[2]*gebco.Tiles; yearTile != gebcoTileTracker {
+				gebcoTileTracker = yearTile
+				gebcoFile := allGebcoFiles[coord[2]][gebcoTile]
+
+				fmt.Println("Loading GEBCO", years[coord[2]], "layer tile:", xGebcoTile, yGebcoTile)
+				var err error
+				gebcoIceTile, gebcoSubIceTile, gebcoTidTile, err = gebcoFile.Load(srcDir)
+				if err != nil {
+					return fmt.Errorf("failed to load GEBCO %d tile layer: %w", years[coord[2]], err)
+				}
+				fmt.Println("Loaded GEBCO", years[coord[2]], "layer tile")
+			}
+
+			xInGebcoTile := coord[0] - (xGebcoTile * gebco.GtiffTileSize)
+			yInGebcoTile := coord[1] - (yGebcoTile * gebco.GtiffTileSize)
+			iceValue := gebcoIceTile.At(xInGebcoTile, yInGebcoTile).(colorext.GrayS16).Y
+			subIceValue := gebcoSubIceTile.At(xInGebcoTile, yInGebcoTile).(colorext.GrayS16).Y
+			tidValue := gebcoTidTile.At(xInGebcoTile, yInGebcoTile).(color.Gray).Y
+
+			dstIterator.SetSample(gopixi.Sample{iceValue, subIceValue, tidValue})
+		}
+		return nil
+	})
+}
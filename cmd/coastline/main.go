@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gracefulearth/gebco"
+)
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to extract the coastline from")
+	dstArg := flag.String("dst", "", "Path to the output GeoJSON file (default standard output)")
+	boundsArg := flag.String("bounds", "-180,-90,180,90", "the region to polygonise as west,south,east,north in decimal degrees")
+	minAreaArg := flag.Float64("minArea", 0, "the area in square kilometres below which islands are dropped")
+	resolutionArg := flag.Float64("resolution", 0, "the approximate pixel size in degrees to polygonise at (0 = full resolution)")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" {
+		flag.Usage()
+		return
+	}
+
+	bounds, err := gebco.ParseBounds(*boundsArg)
+	if err != nil {
+		fmt.Printf("invalid bounds argument: %v\n", err)
+		return
+	}
+
+	dataset, err := gebco.OpenDataset(*srcArg, 256)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	grid := dataset.GridFor(*resolutionArg)
+	if !grid.HasTid() {
+		// the land mask needs type IDs, which only the full resolution layer stores
+		grid = dataset.Grid()
+	}
+	polygons, err := gebco.LandPolygons(grid, gebco.LandPolygonOptions{
+		Region:  bounds,
+		MinArea: *minAreaArg * 1e6,
+	})
+	if err != nil {
+		fmt.Printf("failed to polygonise land: %v\n", err)
+		return
+	}
+
+	var out io.Writer = os.Stdout
+	if *dstArg != "" {
+		dstFile, err := os.Create(*dstArg)
+		if err != nil {
+			fmt.Printf("failed to create output file: %v\n", err)
+			return
+		}
+		defer dstFile.Close()
+		out = dstFile
+	}
+
+	features := make([]gebco.GeoJsonFeature, len(polygons))
+	for i, polygon := range polygons {
+		features[i] = polygon.Feature()
+	}
+	if err := gebco.WriteGeoJson(out, features); err != nil {
+		fmt.Printf("failed to write GeoJSON: %v\n", err)
+		return
+	}
+}
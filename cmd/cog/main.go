@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/gracefulearth/gebco"
+)
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to export")
+	dstArg := flag.String("dst", "", "Path to the output Cloud-Optimized GeoTIFF file")
+	boundsArg := flag.String("bounds", "-180,-90,180,90", "the region to export as west,south,east,north in decimal degrees")
+	channelArg := gebco.GebcoDataIce
+	flag.TextVar(&channelArg, "channel", gebco.GebcoDataIce, "the channel (ice, sub-ice, tid) to export")
+	tileSizeArg := flag.Int("tileSize", 512, "the size of the GeoTIFF tiles (a multiple of 16)")
+	overviewsArg := flag.Int("overviews", -1, "the number of internal overview levels (-1 = until the image fits in one tile)")
+	bigTiffArg := flag.Bool("bigTiff", false, "whether to always write a BigTIFF (otherwise only when the output could exceed 4GB)")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" || *dstArg == "" {
+		flag.Usage()
+		return
+	}
+
+	bounds, err := gebco.ParseBounds(*boundsArg)
+	if err != nil {
+		fmt.Printf("invalid bounds argument: %v\n", err)
+		return
+	}
+
+	dataset, err := gebco.OpenDataset(*srcArg, 256)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	dstFile, err := os.Create(*dstArg)
+	if err != nil {
+		fmt.Printf("failed to create output file: %v\n", err)
+		return
+	}
+	defer dstFile.Close()
+
+	fmt.Printf("Exporting layer %s as a Cloud-Optimized GeoTIFF...\n", dataset.Grid().Name())
+	err = gebco.WriteCog(dstFile, dataset, gebco.CogOptions{
+		Channel:   channelArg,
+		Region:    bounds,
+		TileSize:  *tileSizeArg,
+		Overviews: *overviewsArg,
+		BigTiff:   *bigTiffArg,
+	})
+	if err != nil {
+		fmt.Printf("failed to write COG: %v\n", err)
+		return
+	}
+}
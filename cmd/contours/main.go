@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gracefulearth/gebco"
+)
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to contour")
+	dstArg := flag.String("dst", "", "Path to the output GeoJSON file (default standard output)")
+	boundsArg := flag.String("bounds", "-180,-90,180,90", "the region to contour as west,south,east,north in decimal degrees")
+	surfaceArg := gebco.GebcoDataIce
+	flag.TextVar(&surfaceArg, "surface", gebco.GebcoDataIce, "the surface (ice, sub-ice) to contour")
+	intervalArg := flag.Float64("interval", 0, "the elevation interval in metres between contours (0 = only the given levels)")
+	levelsArg := flag.String("levels", "", "comma separated elevations in metres to contour in addition to the interval (e.g. \"-2500,0\")")
+	resolutionArg := flag.Float64("resolution", 0, "the approximate pixel size in degrees to contour at (0 = full resolution)")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" || (*intervalArg <= 0 && *levelsArg == "") {
+		flag.Usage()
+		return
+	}
+
+	bounds, err := gebco.ParseBounds(*boundsArg)
+	if err != nil {
+		fmt.Printf("invalid bounds argument: %v\n", err)
+		return
+	}
+
+	if surfaceArg == gebco.GebcoDataTypeId {
+		fmt.Printf("invalid surface argument: tid\n")
+		return
+	}
+
+	levels := []float64{}
+	if *intervalArg > 0 {
+		// every multiple of the interval within the range of GEBCO elevations
+		for level := -*intervalArg; level >= -11000; level -= *intervalArg {
+			levels = append(levels, level)
+		}
+		for level := 0.0; level <= 9000; level += *intervalArg {
+			levels = append(levels, level)
+		}
+	}
+	if *levelsArg != "" {
+		for _, part := range strings.Split(*levelsArg, ",") {
+			level, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				fmt.Printf("invalid levels argument: %v\n", err)
+				return
+			}
+			levels = append(levels, level)
+		}
+	}
+	slices.Sort(levels)
+	levels = slices.Compact(levels)
+
+	dataset, err := gebco.OpenDataset(*srcArg, 256)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	grid := dataset.GridFor(*resolutionArg)
+	contours, err := gebco.Contours(grid, gebco.ContourOptions{
+		Surface: surfaceArg,
+		Region:  bounds,
+		Levels:  levels,
+	})
+	if err != nil {
+		fmt.Printf("failed to trace contours: %v\n", err)
+		return
+	}
+
+	var out io.Writer = os.Stdout
+	if *dstArg != "" {
+		dstFile, err := os.Create(*dstArg)
+		if err != nil {
+			fmt.Printf("failed to create output file: %v\n", err)
+			return
+		}
+		defer dstFile.Close()
+		out = dstFile
+	}
+
+	features := make([]gebco.GeoJsonFeature, len(contours))
+	for i, contour := range contours {
+		features[i] = contour.Feature()
+	}
+	if err := gebco.WriteGeoJson(out, features); err != nil {
+		fmt.Printf("failed to write GeoJSON: %v\n", err)
+		return
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/gracefulearth/gebco"
+)
+
+func main() {
+	oldArg := flag.String("old", "", "Path or URL of the older GEBCO Pixi file, or a folder of its GEBCO Geotiff files")
+	newArg := flag.String("new", "", "Path or URL of the newer GEBCO Pixi file, or a folder of its GEBCO Geotiff files (default the older source)")
+	oldYearArg := flag.Int("oldYear", 0, "the older GEBCO year, required for Geotiff folders and stacked Pixi files (0 = the Pixi file's own year)")
+	newYearArg := flag.Int("newYear", 0, "the newer GEBCO year, required for Geotiff folders and stacked Pixi files (0 = the Pixi file's own year)")
+	boundsArg := flag.String("bounds", "", "the region to compare as west,south,east,north in decimal degrees")
+	surfaceArg := gebco.GebcoDataIce
+	flag.TextVar(&surfaceArg, "surface", gebco.GebcoDataIce, "the surface (ice, sub-ice) to difference")
+	cellSizeArg := flag.Float64("cellSize", 1, "the size in degrees of the cells that changes are summarised over")
+	dstArg := flag.String("dst", "", "Path to an output Geotiff of the difference and TID transition bands (default none)")
+	statsArg := flag.String("stats", "", "Path to the output summary statistics (default standard output)")
+	formatArg := flag.String("format", "csv", "the summary statistics format (csv, json)")
+	transitionsArg := flag.String("transitions", "", "Path to an output CSV of TID transition pixel counts (default none)")
+	flag.Parse()
+
+	// validate arguments
+	if *oldArg == "" || *boundsArg == "" {
+		flag.Usage()
+		return
+	}
+	if *newArg == "" {
+		*newArg = *oldArg
+	}
+
+	bounds, err := gebco.ParseBounds(*boundsArg)
+	if err != nil {
+		fmt.Printf("invalid bounds argument: %v\n", err)
+		return
+	}
+
+	if *newArg == *oldArg && *newYearArg == *oldYearArg {
+		fmt.Printf("invalid year arguments: the same source and year are compared\n")
+		return
+	}
+
+	if surfaceArg == gebco.GebcoDataTypeId {
+		fmt.Printf("invalid surface argument: tid\n")
+		return
+	}
+
+	if *cellSizeArg <= 0 {
+		fmt.Printf("invalid cell size argument: %g\n", *cellSizeArg)
+		return
+	}
+
+	if *formatArg != "csv" && *formatArg != "json" {
+		fmt.Printf("invalid format argument: %s\n", *formatArg)
+		return
+	}
+
+	older, closeOlder, err := openRelease(*oldArg, *oldYearArg)
+	if err != nil {
+		fmt.Printf("failed to open older GEBCO release: %v\n", err)
+		return
+	}
+	defer closeOlder()
+
+	newer, closeNewer, err := openRelease(*newArg, *newYearArg)
+	if err != nil {
+		fmt.Printf("failed to open newer GEBCO release: %v\n", err)
+		return
+	}
+	defer closeNewer()
+
+	diff, err := gebco.DiffReleases(older, newer, gebco.DiffOptions{
+		Surface:  surfaceArg,
+		Region:   bounds,
+		CellSize: *cellSizeArg,
+	})
+	if err != nil {
+		fmt.Printf("failed to compare releases: %v\n", err)
+		return
+	}
+
+	if *dstArg != "" {
+		dstFile, err := os.Create(*dstArg)
+		if err != nil {
+			fmt.Printf("failed to create output file: %v\n", err)
+			return
+		}
+		defer dstFile.Close()
+		if err := diff.Raster.WriteGeoTiff(dstFile); err != nil {
+			fmt.Printf("failed to write Geotiff: %v\n", err)
+			return
+		}
+	}
+
+	if *transitionsArg != "" {
+		transitionsFile, err := os.Create(*transitionsArg)
+		if err != nil {
+			fmt.Printf("failed to create transitions file: %v\n", err)
+			return
+		}
+		defer transitionsFile.Close()
+		if err := writeTransitionsCsv(transitionsFile, diff.Transitions); err != nil {
+			fmt.Printf("failed to write transitions: %v\n", err)
+			return
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if *statsArg != "" {
+		statsFile, err := os.Create(*statsArg)
+		if err != nil {
+			fmt.Printf("failed to create statistics file: %v\n", err)
+			return
+		}
+		defer statsFile.Close()
+		out = statsFile
+	}
+
+	if *formatArg == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(diff)
+	} else {
+		err = writeCellsCsv(out, diff)
+	}
+	if err != nil {
+		fmt.Printf("failed to write statistics: %v\n", err)
+		return
+	}
+}
+
+// openRelease opens a GEBCO release as a full resolution grid: a folder of the year's Geotiff files, or a Pixi
+// file, reading the year from its stacked year layer unless it is the file's own year or not given.
+func openRelease(src string, year int) (*gebco.Grid, func() error, error) {
+	if info, err := os.Stat(src); err == nil && info.IsDir() {
+		if year == 0 {
+			return nil, nil, fmt.Errorf("a year is required to read the Geotiff files in %s", src)
+		}
+		grid, err := gebco.OpenGeoTiffGrid(src, year)
+		return grid, func() error { return nil }, err
+	}
+
+	dataset, err := gebco.OpenDataset(src, 256)
+	if err != nil {
+		return nil, nil, err
+	}
+	if year == 0 || (year == dataset.Year() && !slices.Contains(dataset.Years(), year)) {
+		return dataset.Grid(), dataset.Close, nil
+	}
+	grid, err := dataset.YearGrid(year)
+	if err != nil {
+		dataset.Close()
+		return nil, nil, err
+	}
+	return grid, dataset.Close, nil
+}
+
+func writeCellsCsv(out io.Writer, diff *gebco.ReleaseDiff) error {
+	writer := csv.NewWriter(out)
+	err := writer.Write([]string{"west", "south", "east", "north", "pixels", "changed", "mean-change", "max-change", "surveyed", "surveyed-km2", "surveyed-mean-change"})
+	if err != nil {
+		return err
+	}
+	for _, cell := range diff.Cells {
+		err := writer.Write([]string{
+			strconv.FormatFloat(cell.Bounds.West, 'f', -1, 64),
+			strconv.FormatFloat(cell.Bounds.South, 'f', -1, 64),
+			strconv.FormatFloat(cell.Bounds.East, 'f', -1, 64),
+			strconv.FormatFloat(cell.Bounds.North, 'f', -1, 64),
+			strconv.Itoa(cell.Pixels),
+			strconv.Itoa(cell.ChangedPixels),
+			strconv.FormatFloat(cell.MeanChange, 'f', 2, 64),
+			strconv.FormatFloat(cell.MaxChange, 'f', 0, 64),
+			strconv.Itoa(cell.SurveyedPixels),
+			strconv.FormatFloat(cell.SurveyedArea/1e6, 'f', 3, 64),
+			strconv.FormatFloat(cell.SurveyedMeanChange, 'f', 2, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeTransitionsCsv(out io.Writer, transitions []gebco.TidTransition) error {
+	writer := csv.NewWriter(out)
+	if err := writer.Write([]string{"from", "to", "code", "pixels"}); err != nil {
+		return err
+	}
+	for _, transition := range transitions {
+		err := writer.Write([]string{
+			strconv.Itoa(int(transition.From)),
+			strconv.Itoa(int(transition.To)),
+			strconv.Itoa(gebco.TidTransitionCode(transition.From, transition.To)),
+			strconv.Itoa(transition.Pixels),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
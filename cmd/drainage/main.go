@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gracefulearth/gebco"
+	"github.com/gracefulearth/gopixi"
+)
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to drain")
+	boundsArg := flag.String("bounds", "-180,-90,180,90", "the region to drain as west,south,east,north in decimal degrees")
+	resolutionArg := flag.Float64("resolution", 0, "the approximate pixel size in degrees to drain at (0 = full resolution)")
+	oceanOnlyArg := flag.Bool("oceanOnly", true, "whether to drain only the seafloor, treating land as walls")
+	minDepthArg := flag.Float64("minDepth", 100, "the depth in metres of a basin below its spill point below which it is merged into a neighbour")
+	minAreaArg := flag.Float64("minArea", 0, "the area in square kilometres below the spill point below which a basin is merged into a neighbour")
+	rasterArg := flag.String("raster", "", "Path to an output raster of flow direction, flow accumulation and basin id, a Pixi file if it ends in .pixi and a Geotiff otherwise (default none)")
+	polygonsArg := flag.String("polygons", "", "Path to an output GeoJSON of basin polygons (default none)")
+	statsArg := flag.String("stats", "", "Path to the output basin statistics (default standard output)")
+	formatArg := flag.String("format", "csv", "the basin statistics format (csv, json)")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" {
+		flag.Usage()
+		return
+	}
+
+	bounds, err := gebco.ParseBounds(*boundsArg)
+	if err != nil {
+		fmt.Printf("invalid bounds argument: %v\n", err)
+		return
+	}
+
+	if *formatArg != "csv" && *formatArg != "json" {
+		fmt.Printf("invalid format argument: %s\n", *formatArg)
+		return
+	}
+
+	dataset, err := gebco.OpenDataset(*srcArg, 256)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	drainage, err := gebco.DrainageBasins(dataset.GridFor(*resolutionArg), gebco.DrainageOptions{
+		Region:    bounds,
+		OceanOnly: *oceanOnlyArg,
+		MinDepth:  *minDepthArg,
+		MinArea:   *minAreaArg * 1e6,
+	})
+	if err != nil {
+		fmt.Printf("failed to drain region: %v\n", err)
+		return
+	}
+
+	if *rasterArg != "" {
+		rasterFile, err := os.Create(*rasterArg)
+		if err != nil {
+			fmt.Printf("failed to create raster file: %v\n", err)
+			return
+		}
+		defer rasterFile.Close()
+		if filepath.Ext(*rasterArg) == ".pixi" {
+			err = drainage.Raster.WritePixi(rasterFile, "gebco_drainage", 512, gopixi.WithCompression(gopixi.CompressionFlate))
+		} else {
+			err = drainage.Raster.WriteGeoTiff(rasterFile)
+		}
+		if err != nil {
+			fmt.Printf("failed to write raster: %v\n", err)
+			return
+		}
+	}
+
+	if *polygonsArg != "" {
+		polygonsFile, err := os.Create(*polygonsArg)
+		if err != nil {
+			fmt.Printf("failed to create polygons file: %v\n", err)
+			return
+		}
+		defer polygonsFile.Close()
+		features := make([]gebco.GeoJsonFeature, len(drainage.Basins))
+		for i, basin := range drainage.Basins {
+			features[i] = basin.Feature()
+		}
+		if err := gebco.WriteGeoJson(polygonsFile, features); err != nil {
+			fmt.Printf("failed to write GeoJSON: %v\n", err)
+			return
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if *statsArg != "" {
+		statsFile, err := os.Create(*statsArg)
+		if err != nil {
+			fmt.Printf("failed to create statistics file: %v\n", err)
+			return
+		}
+		defer statsFile.Close()
+		out = statsFile
+	}
+
+	if *formatArg == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(drainage)
+	} else {
+		err = writeBasinsCsv(out, drainage.Basins)
+	}
+	if err != nil {
+		fmt.Printf("failed to write statistics: %v\n", err)
+		return
+	}
+}
+
+func writeBasinsCsv(out io.Writer, basins []gebco.Basin) error {
+	writer := csv.NewWriter(out)
+	err := writer.Write([]string{"id", "area-km2", "pixels", "pit-lat", "pit-lng", "pit-depth", "spill-depth", "relief"})
+	if err != nil {
+		return err
+	}
+	for _, basin := range basins {
+		err := writer.Write([]string{
+			strconv.Itoa(basin.Id),
+			strconv.FormatFloat(basin.Area/1e6, 'f', 1, 64),
+			strconv.Itoa(basin.Pixels),
+			strconv.FormatFloat(basin.Pit.Lat, 'f', 6, 64),
+			strconv.FormatFloat(basin.Pit.Lng, 'f', 6, 64),
+			strconv.FormatFloat(basin.PitDepth, 'f', 0, 64),
+			strconv.FormatFloat(basin.SpillDepth, 'f', 0, 64),
+			strconv.FormatFloat(basin.Relief, 'f', 0, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/gracefulearth/gebco"
+)
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to search for survey gaps")
+	dstArg := flag.String("dst", "", "Path to the output file (default standard output)")
+	boundsArg := flag.String("bounds", "-180,-90,180,90", "the region to search as west,south,east,north in decimal degrees")
+	minAreaArg := flag.Float64("minArea", 1000, "the area in square kilometres below which gaps are dropped")
+	orderArg := gebco.SurveyGapsByArea
+	flag.TextVar(&orderArg, "order", gebco.SurveyGapsByArea, "the order to rank gaps in (area, depth, latitude)")
+	limitArg := flag.Int("limit", 0, "the number of top ranked gaps to write (0 = all)")
+	formatArg := flag.String("format", "geojson", "the output format (geojson, csv)")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" {
+		flag.Usage()
+		return
+	}
+
+	bounds, err := gebco.ParseBounds(*boundsArg)
+	if err != nil {
+		fmt.Printf("invalid bounds argument: %v\n", err)
+		return
+	}
+
+	if *limitArg < 0 {
+		fmt.Printf("invalid limit argument: %d\n", *limitArg)
+		return
+	}
+
+	if *formatArg != "geojson" && *formatArg != "csv" {
+		fmt.Printf("invalid format argument: %s\n", *formatArg)
+		return
+	}
+
+	dataset, err := gebco.OpenDataset(*srcArg, 256)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	// type IDs are only stored in the full resolution layer
+	gaps, err := gebco.SurveyGaps(dataset.Grid(), gebco.SurveyGapOptions{
+		Region:  bounds,
+		MinArea: *minAreaArg * 1e6,
+		Order:   orderArg,
+	})
+	if err != nil {
+		fmt.Printf("failed to find survey gaps: %v\n", err)
+		return
+	}
+	if *limitArg > 0 && len(gaps) > *limitArg {
+		gaps = gaps[:*limitArg]
+	}
+
+	var out io.Writer = os.Stdout
+	if *dstArg != "" {
+		dstFile, err := os.Create(*dstArg)
+		if err != nil {
+			fmt.Printf("failed to create output file: %v\n", err)
+			return
+		}
+		defer dstFile.Close()
+		out = dstFile
+	}
+
+	if *formatArg == "csv" {
+		err = writeCsv(out, gaps)
+	} else {
+		features := make([]gebco.GeoJsonFeature, len(gaps))
+		for i, gap := range gaps {
+			features[i] = gap.Feature()
+		}
+		err = gebco.WriteGeoJson(out, features)
+	}
+	if err != nil {
+		fmt.Printf("failed to write survey gaps: %v\n", err)
+		return
+	}
+}
+
+func writeCsv(out io.Writer, gaps []gebco.SurveyGap) error {
+	writer := csv.NewWriter(out)
+	if err := writer.Write([]string{"rank", "area-km2", "pixels", "min-depth", "max-depth", "centroid-lat", "centroid-lng"}); err != nil {
+		return err
+	}
+	for i, gap := range gaps {
+		err := writer.Write([]string{
+			strconv.Itoa(i + 1),
+			strconv.FormatFloat(gap.Area/1e6, 'f', 1, 64),
+			strconv.Itoa(gap.Pixels),
+			strconv.FormatFloat(gap.MinDepth, 'f', 0, 64),
+			strconv.FormatFloat(gap.MaxDepth, 'f', 0, 64),
+			strconv.FormatFloat(gap.Centroid.Lat, 'f', 6, 64),
+			strconv.FormatFloat(gap.Centroid.Lng, 'f', 6, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
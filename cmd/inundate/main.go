@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gracefulearth/gebco"
+	"github.com/gracefulearth/gopixi"
+)
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to flood")
+	boundsArg := flag.String("bounds", "", "the region to flood as west,south,east,north in decimal degrees")
+	seaLevelArg := flag.Float64("seaLevel", 0, "the rise in sea level in metres")
+	resolutionArg := flag.Float64("resolution", 0, "the approximate pixel size in degrees to flood at (0 = full resolution)")
+	cellSizeArg := flag.Float64("cellSize", 1, "the size in degrees of the cells that the flooded area is summarised over")
+	minAreaArg := flag.Float64("minArea", 0, "the area in square kilometres below which flooded polygons are dropped")
+	oceanArg := flag.String("ocean", "", "positions in the ocean to also flood from as semicolon separated lat,lng pairs, for oceans not reaching the edges of the region (default none)")
+	maskArg := flag.String("mask", "", "Path to an output flooded mask, a Pixi file if it ends in .pixi and a Geotiff otherwise (default none)")
+	polygonsArg := flag.String("polygons", "", "Path to an output GeoJSON of flooded polygons (default none)")
+	statsArg := flag.String("stats", "", "Path to the output summary statistics (default standard output)")
+	formatArg := flag.String("format", "csv", "the summary statistics format (csv, json)")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" || *boundsArg == "" || *seaLevelArg <= 0 {
+		flag.Usage()
+		return
+	}
+
+	bounds, err := gebco.ParseBounds(*boundsArg)
+	if err != nil {
+		fmt.Printf("invalid bounds argument: %v\n", err)
+		return
+	}
+
+	oceanSeeds := []gebco.LatLng{}
+	if *oceanArg != "" {
+		oceanSeeds, err = gebco.ParseLatLngs(*oceanArg)
+		if err != nil {
+			fmt.Printf("invalid ocean argument: %v\n", err)
+			return
+		}
+	}
+
+	if *cellSizeArg <= 0 {
+		fmt.Printf("invalid cell size argument: %g\n", *cellSizeArg)
+		return
+	}
+
+	if *formatArg != "csv" && *formatArg != "json" {
+		fmt.Printf("invalid format argument: %s\n", *formatArg)
+		return
+	}
+
+	dataset, err := gebco.OpenDataset(*srcArg, 256)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	grid := dataset.GridFor(*resolutionArg)
+	if !grid.HasTid() {
+		// the land mask needs type IDs, which only the full resolution layer stores
+		grid = dataset.Grid()
+	}
+	inundation, err := gebco.Inundate(grid, gebco.InundationOptions{
+		Region:     bounds,
+		SeaLevel:   *seaLevelArg,
+		CellSize:   *cellSizeArg,
+		MinArea:    *minAreaArg * 1e6,
+		OceanSeeds: oceanSeeds,
+	})
+	if err != nil {
+		fmt.Printf("failed to flood region: %v\n", err)
+		return
+	}
+
+	if *maskArg != "" {
+		maskFile, err := os.Create(*maskArg)
+		if err != nil {
+			fmt.Printf("failed to create mask file: %v\n", err)
+			return
+		}
+		defer maskFile.Close()
+		if filepath.Ext(*maskArg) == ".pixi" {
+			err = inundation.Raster.WritePixi(maskFile, "gebco_inundation", 512, gopixi.WithCompression(gopixi.CompressionFlate))
+		} else {
+			err = inundation.Raster.WriteGeoTiff(maskFile)
+		}
+		if err != nil {
+			fmt.Printf("failed to write mask: %v\n", err)
+			return
+		}
+	}
+
+	if *polygonsArg != "" {
+		polygonsFile, err := os.Create(*polygonsArg)
+		if err != nil {
+			fmt.Printf("failed to create polygons file: %v\n", err)
+			return
+		}
+		defer polygonsFile.Close()
+		features := make([]gebco.GeoJsonFeature, len(inundation.Polygons))
+		for i, polygon := range inundation.Polygons {
+			features[i] = gebco.NewGeoJsonFeature(polygon.Geometry(), map[string]any{"areaKm2": polygon.Area / 1e6})
+		}
+		if err := gebco.WriteGeoJson(polygonsFile, features); err != nil {
+			fmt.Printf("failed to write GeoJSON: %v\n", err)
+			return
+		}
+	}
+
+	var out io.Writer = os.Stdout
+	if *statsArg != "" {
+		statsFile, err := os.Create(*statsArg)
+		if err != nil {
+			fmt.Printf("failed to create statistics file: %v\n", err)
+			return
+		}
+		defer statsFile.Close()
+		out = statsFile
+	}
+
+	if *formatArg == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(inundation)
+	} else {
+		err = writeCellsCsv(out, inundation)
+	}
+	if err != nil {
+		fmt.Printf("failed to write statistics: %v\n", err)
+		return
+	}
+}
+
+func writeCellsCsv(out io.Writer, inundation *gebco.Inundation) error {
+	writer := csv.NewWriter(out)
+	err := writer.Write([]string{"west", "south", "east", "north", "land", "land-km2", "flooded", "flooded-km2"})
+	if err != nil {
+		return err
+	}
+	for _, cell := range inundation.Cells {
+		err := writer.Write([]string{
+			strconv.FormatFloat(cell.Bounds.West, 'f', -1, 64),
+			strconv.FormatFloat(cell.Bounds.South, 'f', -1, 64),
+			strconv.FormatFloat(cell.Bounds.East, 'f', -1, 64),
+			strconv.FormatFloat(cell.Bounds.North, 'f', -1, 64),
+			strconv.Itoa(cell.LandPixels),
+			strconv.FormatFloat(cell.LandArea/1e6, 'f', 3, 64),
+			strconv.Itoa(cell.FloodedPixels),
+			strconv.FormatFloat(cell.FloodedArea/1e6, 'f', 3, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
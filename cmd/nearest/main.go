@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/gracefulearth/gebco"
+)
+
+// result is the nearest match to a position, if any.
+type result struct {
+	From   gebco.LatLng        `json:"from"`
+	Status gebco.NearestStatus `json:"status"`
+	Match  *gebco.NearestMatch `json:"match,omitempty"`
+}
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to search")
+	fromArg := flag.String("from", "", "the positions to search from as semicolon separated lat,lng pairs (e.g. \"50.1,-5.5;40.7,-74.0\")")
+	dstArg := flag.String("dst", "", "Path to the output file (default standard output)")
+	resolutionArg := flag.Float64("resolution", 0, "the approximate pixel size in degrees to search at (0 = full resolution)")
+	maxRadiusArg := flag.Float64("maxRadius", 100, "the greatest distance in kilometres to search from each position")
+	surfaceArg := gebco.GebcoDataSubIce
+	flag.TextVar(&surfaceArg, "surface", gebco.GebcoDataSubIce, "the surface (ice, sub-ice) the depth criteria apply to")
+	minDepthArg := flag.Float64("minDepth", math.NaN(), "the depth in metres the match must be at or below (NaN = any)")
+	maxDepthArg := flag.Float64("maxDepth", math.NaN(), "the depth in metres the match must be at or above (NaN = any)")
+	landArg := flag.Bool("land", false, "whether the match must be land")
+	seaArg := flag.Bool("sea", false, "whether the match must be sea")
+	directArg := flag.Bool("direct", false, "whether the match must have been measured directly")
+	maxPixelsArg := flag.Int("maxPixels", 0, "the number of pixels each search may visit before giving up, reporting the position as truncated (0 = no limit)")
+	formatArg := flag.String("format", "csv", "the output format (csv, json)")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" || *fromArg == "" {
+		flag.Usage()
+		return
+	}
+
+	positions, err := gebco.ParseLatLngs(*fromArg)
+	if err != nil {
+		fmt.Printf("invalid from argument: %v\n", err)
+		return
+	}
+
+	if *landArg && *seaArg {
+		fmt.Printf("invalid land and sea arguments: a match cannot be both\n")
+		return
+	}
+	if !*landArg && !*seaArg && !*directArg && math.IsNaN(*minDepthArg) && math.IsNaN(*maxDepthArg) {
+		fmt.Printf("missing criteria: expected at least one of minDepth, maxDepth, land, sea or direct\n")
+		return
+	}
+
+	if *formatArg != "csv" && *formatArg != "json" {
+		fmt.Printf("invalid format argument: %s\n", *formatArg)
+		return
+	}
+
+	dataset, err := gebco.OpenDataset(*srcArg, 256)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	// every criterion given must hold, with depths positive below sea level
+	match := func(sample gebco.GebcoSample) bool {
+		depth := -sample.Value(surfaceArg)
+		return !(depth < *minDepthArg) && !(depth > *maxDepthArg) &&
+			(!*landArg || sample.IsLand()) && (!*seaArg || !sample.IsLand()) && (!*directArg || sample.Tid.IsDirect())
+	}
+	opts := gebco.NearestOptions{MaxRadius: *maxRadiusArg * 1000, Match: match, MaxPixels: *maxPixelsArg}
+
+	grid := dataset.GridFor(*resolutionArg)
+	results := []result{}
+	for _, from := range positions {
+		nearest, status, err := gebco.Nearest(grid, from, opts)
+		if err != nil {
+			fmt.Printf("failed to search from %v: %v\n", from, err)
+			return
+		}
+		r := result{From: from, Status: status}
+		if status == gebco.NearestFound {
+			r.Match = &nearest
+		}
+		results = append(results, r)
+	}
+
+	var out io.Writer = os.Stdout
+	if *dstArg != "" {
+		dstFile, err := os.Create(*dstArg)
+		if err != nil {
+			fmt.Printf("failed to create output file: %v\n", err)
+			return
+		}
+		defer dstFile.Close()
+		out = dstFile
+	}
+
+	if *formatArg == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(results)
+	} else {
+		err = writeCsv(out, results)
+	}
+	if err != nil {
+		fmt.Printf("failed to write matches: %v\n", err)
+		return
+	}
+}
+
+func writeCsv(out io.Writer, results []result) error {
+	writer := csv.NewWriter(out)
+	err := writer.Write([]string{"from-lat", "from-lng", "status", "lat", "lng", "distance-km", "bearing", "ice", "sub-ice", "tid"})
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		row := []string{
+			strconv.FormatFloat(r.From.Lat, 'f', 6, 64),
+			strconv.FormatFloat(r.From.Lng, 'f', 6, 64),
+			r.Status.String(),
+		}
+		if r.Match == nil {
+			row = append(row, "", "", "", "", "", "", "")
+		} else {
+			row = append(row,
+				strconv.FormatFloat(r.Match.Position.Lat, 'f', 6, 64),
+				strconv.FormatFloat(r.Match.Position.Lng, 'f', 6, 64),
+				strconv.FormatFloat(r.Match.Distance/1000, 'f', 3, 64),
+				strconv.FormatFloat(r.Match.Bearing, 'f', 1, 64),
+				strconv.Itoa(int(r.Match.Ice)),
+				strconv.Itoa(int(r.Match.SubIce)),
+				strconv.Itoa(int(r.Match.Tid)),
+			)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
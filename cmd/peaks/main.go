@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/gracefulearth/gebco"
+)
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to search for peaks")
+	dstArg := flag.String("dst", "", "Path to the output file (default standard output)")
+	boundsArg := flag.String("bounds", "-180,-90,180,90", "the region to search as west,south,east,north in decimal degrees")
+	resolutionArg := flag.Float64("resolution", 0, "the approximate pixel size in degrees to search at (0 = full resolution)")
+	minHeightArg := flag.Float64("minHeight", 1000, "the height in metres of a summit above its base below which peaks are dropped")
+	minAreaArg := flag.Float64("minArea", 0, "the footprint area in square kilometres below which peaks are dropped")
+	submarineArg := flag.Bool("submarine", true, "whether to keep only peaks with summits below sea level")
+	limitArg := flag.Int("limit", 0, "the number of highest peaks to write (0 = all)")
+	formatArg := flag.String("format", "geojson", "the output format (geojson, csv)")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" {
+		flag.Usage()
+		return
+	}
+
+	bounds, err := gebco.ParseBounds(*boundsArg)
+	if err != nil {
+		fmt.Printf("invalid bounds argument: %v\n", err)
+		return
+	}
+
+	if *limitArg < 0 {
+		fmt.Printf("invalid limit argument: %d\n", *limitArg)
+		return
+	}
+
+	if *formatArg != "geojson" && *formatArg != "csv" {
+		fmt.Printf("invalid format argument: %s\n", *formatArg)
+		return
+	}
+
+	dataset, err := gebco.OpenDataset(*srcArg, 256)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	peaks, err := gebco.Peaks(dataset.GridFor(*resolutionArg), gebco.PeakOptions{
+		Region:    bounds,
+		MinHeight: *minHeightArg,
+		MinArea:   *minAreaArg * 1e6,
+		Submarine: *submarineArg,
+	})
+	if err != nil {
+		fmt.Printf("failed to find peaks: %v\n", err)
+		return
+	}
+	if *limitArg > 0 && len(peaks) > *limitArg {
+		peaks = peaks[:*limitArg]
+	}
+
+	var out io.Writer = os.Stdout
+	if *dstArg != "" {
+		dstFile, err := os.Create(*dstArg)
+		if err != nil {
+			fmt.Printf("failed to create output file: %v\n", err)
+			return
+		}
+		defer dstFile.Close()
+		out = dstFile
+	}
+
+	if *formatArg == "csv" {
+		err = writeCsv(out, peaks)
+	} else {
+		features := make([]gebco.GeoJsonFeature, len(peaks))
+		for i, peak := range peaks {
+			features[i] = peak.Feature()
+		}
+		err = gebco.WriteGeoJson(out, features)
+	}
+	if err != nil {
+		fmt.Printf("failed to write peaks: %v\n", err)
+		return
+	}
+}
+
+func writeCsv(out io.Writer, peaks []gebco.Peak) error {
+	writer := csv.NewWriter(out)
+	if err := writer.Write([]string{"rank", "lat", "lng", "summit-depth", "base-depth", "height", "pixels", "area-km2"}); err != nil {
+		return err
+	}
+	for i, peak := range peaks {
+		err := writer.Write([]string{
+			strconv.Itoa(i + 1),
+			strconv.FormatFloat(peak.Summit.Lat, 'f', 6, 64),
+			strconv.FormatFloat(peak.Summit.Lng, 'f', 6, 64),
+			strconv.FormatFloat(peak.SummitDepth, 'f', 0, 64),
+			strconv.FormatFloat(peak.BaseDepth, 'f', 0, 64),
+			strconv.FormatFloat(peak.Height, 'f', 0, 64),
+			strconv.Itoa(peak.Pixels),
+			strconv.FormatFloat(peak.Area/1e6, 'f', 1, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gracefulearth/gebco"
+)
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to sample")
+	pathArg := flag.String("path", "", "the waypoints of the profile as semicolon separated lat,lng pairs (e.g. \"50.1,-5.5;40.7,-74.0\")")
+	spacingArg := flag.Float64("spacing", 0, "the distance in metres between samples along the path (0 = one GEBCO cell)")
+	formatArg := flag.String("format", "csv", "the output format (csv, json)")
+	dstArg := flag.String("dst", "", "Path to the output file (default standard output)")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" || *pathArg == "" {
+		flag.Usage()
+		return
+	}
+
+	waypoints, err := gebco.ParseLatLngs(*pathArg)
+	if err != nil {
+		fmt.Printf("invalid path argument: %v\n", err)
+		return
+	}
+
+	if *formatArg != "csv" && *formatArg != "json" {
+		fmt.Printf("invalid format argument: %s\n", *formatArg)
+		return
+	}
+
+	dataset, err := gebco.OpenDataset(*srcArg, 16)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	samples, err := gebco.Profile(dataset.Grid(), waypoints, *spacingArg)
+	if err != nil {
+		fmt.Printf("failed to sample profile: %v\n", err)
+		return
+	}
+
+	var out io.Writer = os.Stdout
+	if *dstArg != "" {
+		dstFile, err := os.Create(*dstArg)
+		if err != nil {
+			fmt.Printf("failed to create output file: %v\n", err)
+			return
+		}
+		defer dstFile.Close()
+		out = dstFile
+	}
+
+	if *formatArg == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(samples)
+	} else {
+		err = gebco.WriteProfileCsv(out, samples)
+	}
+	if err != nil {
+		fmt.Printf("failed to write profile: %v\n", err)
+		return
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gracefulearth/gebco"
+)
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to query")
+	pointsArg := flag.String("points", "", "Path to a CSV file of positions with a header row (default standard input)")
+	dstArg := flag.String("dst", "", "Path to the output file (default standard output)")
+	latArg := flag.String("lat", "lat", "the name of the latitude column in decimal degrees")
+	lngArg := flag.String("lng", "lng", "the name of the longitude column in decimal degrees")
+	surfaceArg := gebco.GebcoDataIce
+	flag.TextVar(&surfaceArg, "surface", gebco.GebcoDataIce, "the surface (ice, sub-ice) to interpolate")
+	interpolationArg := gebco.InterpolateBilinear
+	flag.TextVar(&interpolationArg, "interpolation", gebco.InterpolateBilinear, "how to interpolate the surface at each position (nearest, bilinear, bicubic)")
+	batchArg := flag.Int("batch", 1000000, "the number of rows to read, sort by tile and query at a time")
+	workersArg := flag.Int("workers", 0, "the number of tiles to query in parallel (0 = one per processor)")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" {
+		flag.Usage()
+		return
+	}
+	if *batchArg <= 0 {
+		fmt.Printf("invalid batch argument: %d\n", *batchArg)
+		return
+	}
+
+	var in io.Reader = os.Stdin
+	if *pointsArg != "" {
+		pointsFile, err := os.Open(*pointsArg)
+		if err != nil {
+			fmt.Printf("failed to open points file: %v\n", err)
+			return
+		}
+		defer pointsFile.Close()
+		in = pointsFile
+	}
+
+	dataset, err := gebco.OpenDataset(*srcArg, 256)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	var out io.Writer = os.Stdout
+	if *dstArg != "" {
+		dstFile, err := os.Create(*dstArg)
+		if err != nil {
+			fmt.Printf("failed to create output file: %v\n", err)
+			return
+		}
+		defer dstFile.Close()
+		out = dstFile
+	}
+
+	reader := csv.NewReader(in)
+	writer := csv.NewWriter(out)
+	header, err := reader.Read()
+	if err != nil {
+		fmt.Printf("failed to read header: %v\n", err)
+		return
+	}
+	latColumn, lngColumn := slices.Index(header, *latArg), slices.Index(header, *lngArg)
+	if latColumn < 0 || lngColumn < 0 {
+		fmt.Printf("missing %s or %s column in header %v\n", *latArg, *lngArg, header)
+		return
+	}
+	if err := writer.Write(append(header, "ice", "sub-ice", "tid", "interpolated")); err != nil {
+		fmt.Printf("failed to write rows: %v\n", err)
+		return
+	}
+
+	opts := gebco.QueryOptions{Surface: surfaceArg, Interpolation: interpolationArg, Workers: *workersArg}
+	for {
+		rows, err := readBatch(reader, *batchArg)
+		if err != nil {
+			fmt.Printf("failed to read rows: %v\n", err)
+			return
+		}
+		if len(rows) == 0 {
+			break
+		}
+		if err := queryBatch(dataset.Grid(), rows, latColumn, lngColumn, opts, writer); err != nil {
+			fmt.Printf("failed to query rows: %v\n", err)
+			return
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		fmt.Printf("failed to write rows: %v\n", err)
+		return
+	}
+}
+
+// readBatch reads up to size rows, returning none at the end of the input.
+func readBatch(reader *csv.Reader, size int) ([][]string, error) {
+	rows := [][]string{}
+	for len(rows) < size {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// queryBatch queries the positions of a batch of rows and writes the rows back with their values appended, leaving
+// the values empty for rows without a valid position.
+func queryBatch(grid *gebco.Grid, rows [][]string, latColumn, lngColumn int, opts gebco.QueryOptions, writer *csv.Writer) error {
+	positions := []gebco.LatLng{}
+	valid := make([]int, len(rows))
+	for i, row := range rows {
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(row[latColumn]), 64)
+		lng, lngErr := strconv.ParseFloat(strings.TrimSpace(row[lngColumn]), 64)
+		if latErr != nil || lngErr != nil || math.IsNaN(lng) || math.IsInf(lng, 0) || !(lat >= -90 && lat <= 90) {
+			valid[i] = -1
+			continue
+		}
+		valid[i] = len(positions)
+		positions = append(positions, gebco.LatLng{Lat: lat, Lng: lng})
+	}
+	values, err := gebco.QueryPoints(grid, positions, opts)
+	if err != nil {
+		return err
+	}
+	for i, row := range rows {
+		if valid[i] < 0 {
+			row = append(row, "", "", "", "")
+		} else {
+			value := values[valid[i]]
+			row = append(row,
+				strconv.Itoa(int(value.Ice)),
+				strconv.Itoa(int(value.SubIce)),
+				strconv.Itoa(int(value.Tid)),
+				strconv.FormatFloat(value.Interpolated, 'f', 1, 64),
+			)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/png"
+	"math"
+	"os"
+
+	"github.com/gracefulearth/gebco"
+)
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to render")
+	dstArg := flag.String("dst", "", "Path to the output PNG file")
+	boundsArg := flag.String("bounds", "-180,-90,180,90", "the region to render as west,south,east,north in decimal degrees")
+	widthArg := flag.Int("width", 0, "the width of the image in pixels (0 = the width of the coarsest layer covering the region at full detail, capped at 4096)")
+	heightArg := flag.Int("height", 0, "the height of the image in pixels (0 = keep the aspect ratio of the region)")
+	surfaceArg := gebco.GebcoDataIce
+	flag.TextVar(&surfaceArg, "surface", gebco.GebcoDataIce, "the values (ice, sub-ice, tid) to render")
+	cptArg := flag.String("cpt", "", "Path to a GMT .cpt colour palette (default the built-in GEBCO ramp, or TID palette for tid)")
+	hillshadeArg := flag.Bool("hillshade", false, "whether to shade the relief with a multidirectional hillshade")
+	exaggerationArg := flag.Float64("exaggeration", 1, "the vertical exaggeration of the hillshade")
+	landArg := flag.String("land", "color", "how to draw land (color, transparent, mask)")
+	maskColorArg := flag.String("maskColor", "#d9d9d9", "the colour of land when drawn as a mask (#rrggbb or #rrggbbaa)")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" || *dstArg == "" {
+		flag.Usage()
+		return
+	}
+
+	bounds, err := gebco.ParseBounds(*boundsArg)
+	if err != nil {
+		fmt.Printf("invalid bounds argument: %v\n", err)
+		return
+	}
+
+	opts := gebco.RenderOptions{
+		Surface:      surfaceArg,
+		Hillshade:    *hillshadeArg,
+		Exaggeration: *exaggerationArg,
+	}
+	switch *landArg {
+	case "color":
+		opts.Land = gebco.LandColored
+	case "transparent":
+		opts.Land = gebco.LandTransparent
+	case "mask":
+		opts.Land = gebco.LandMasked
+	default:
+		fmt.Printf("invalid land argument: %s\n", *landArg)
+		return
+	}
+	opts.MaskColor, err = gebco.ParseHexColor(*maskColorArg)
+	if err != nil {
+		fmt.Printf("invalid mask color argument: %v\n", err)
+		return
+	}
+	if *cptArg != "" {
+		opts.Palette, err = gebco.LoadCpt(*cptArg)
+		if err != nil {
+			fmt.Printf("invalid cpt argument: %v\n", err)
+			return
+		}
+	}
+
+	dataset, err := gebco.OpenDataset(*srcArg, 64)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	width, height := *widthArg, *heightArg
+	if width <= 0 {
+		coarsest := dataset.Grids()[len(dataset.Grids())-1]
+		width = min(4096, int(math.Ceil(bounds.Width()/coarsest.DegreesPerPixel())))
+	}
+	if height <= 0 {
+		height = max(1, int(math.Round(float64(width)*bounds.Height()/bounds.Width())))
+	}
+
+	grid := dataset.GridFor(math.Min(bounds.Width()/float64(width), bounds.Height()/float64(height)))
+	if surfaceArg == gebco.GebcoDataTypeId && !grid.HasTid() {
+		// only the full resolution layer stores type IDs
+		grid = dataset.Grid()
+	}
+	fmt.Printf("Rendering %dx%d image from layer %s...\n", width, height, grid.Name())
+	img, err := gebco.Render(grid, bounds, width, height, opts)
+	if err != nil {
+		fmt.Printf("failed to render image: %v\n", err)
+		return
+	}
+
+	dstFile, err := os.Create(*dstArg)
+	if err != nil {
+		fmt.Printf("failed to create output file: %v\n", err)
+		return
+	}
+	defer dstFile.Close()
+
+	if err := png.Encode(dstFile, img); err != nil {
+		fmt.Printf("failed to write PNG: %v\n", err)
+		return
+	}
+}
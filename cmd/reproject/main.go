@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gracefulearth/gebco"
+	"github.com/gracefulearth/gopixi"
+)
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to reproject")
+	dstArg := flag.String("dst", "", "Path to the output file")
+	epsgArg := flag.Int("epsg", 3413, "the EPSG code of the target projection (4326, 3857, 3413, 3031)")
+	boundsArg := flag.String("bounds", "", "the extent of the output as minX,minY,maxX,maxY in target projection units")
+	resolutionArg := flag.Float64("resolution", 1000, "the size of an output pixel in target projection units")
+	interpolationArg := gebco.InterpolateBilinear
+	flag.TextVar(&interpolationArg, "interpolation", gebco.InterpolateBilinear, "how to resample the grid (nearest, bilinear, bicubic)")
+	channelsArg := flag.String("channels", "ice", "comma separated channels (ice, sub-ice, tid) to write as bands")
+	formatArg := flag.String("format", "geotiff", "the output format (geotiff, pixi)")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" || *dstArg == "" || *boundsArg == "" {
+		flag.Usage()
+		return
+	}
+
+	projection, err := gebco.ProjectionForEpsg(*epsgArg)
+	if err != nil {
+		fmt.Printf("invalid epsg argument: %v\n", err)
+		return
+	}
+
+	extent := strings.Split(*boundsArg, ",")
+	if len(extent) != 4 {
+		fmt.Printf("invalid bounds argument: expected minX,minY,maxX,maxY\n")
+		return
+	}
+	var corners [4]float64
+	for i, part := range extent {
+		corners[i], err = strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			fmt.Printf("invalid bounds argument: %v\n", err)
+			return
+		}
+	}
+
+	channels := []gebco.GebcoDataType{}
+	for _, name := range strings.Split(*channelsArg, ",") {
+		var channel gebco.GebcoDataType
+		if err := channel.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+			fmt.Printf("invalid channels argument: %v\n", err)
+			return
+		}
+		channels = append(channels, channel)
+	}
+
+	if *formatArg != "geotiff" && *formatArg != "pixi" {
+		fmt.Printf("invalid format argument: %s\n", *formatArg)
+		return
+	}
+
+	dataset, err := gebco.OpenDataset(*srcArg, 64)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	// pick the coarsest layer that still resolves an output pixel
+	degreesPerPixel := *resolutionArg
+	if projection.EPSG() != 4326 {
+		degreesPerPixel = *resolutionArg / (math.Pi * gebco.EarthRadius / 180)
+	}
+	grid := dataset.GridFor(degreesPerPixel)
+	for _, channel := range channels {
+		if channel == gebco.GebcoDataTypeId && !grid.HasTid() {
+			// only the full resolution layer stores type IDs
+			grid = dataset.Grid()
+		}
+	}
+
+	fmt.Printf("Reprojecting layer %s to EPSG:%d...\n", grid.Name(), projection.EPSG())
+	raster, err := gebco.Reproject(grid, gebco.ReprojectOptions{
+		Projection:    projection,
+		MinX:          corners[0],
+		MinY:          corners[1],
+		MaxX:          corners[2],
+		MaxY:          corners[3],
+		Resolution:    *resolutionArg,
+		Interpolation: interpolationArg,
+		Channels:      channels,
+	})
+	if err != nil {
+		fmt.Printf("failed to reproject: %v\n", err)
+		return
+	}
+
+	dstFile, err := os.Create(*dstArg)
+	if err != nil {
+		fmt.Printf("failed to create output file: %v\n", err)
+		return
+	}
+	defer dstFile.Close()
+
+	fmt.Printf("Writing %dx%d raster...\n", raster.Width, raster.Height)
+	if *formatArg == "pixi" {
+		err = raster.WritePixi(dstFile, "gebco_reprojected", 512, gopixi.WithCompression(gopixi.CompressionFlate))
+	} else {
+		err = raster.WriteGeoTiff(dstFile)
+	}
+	if err != nil {
+		fmt.Printf("failed to write output: %v\n", err)
+		return
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/gracefulearth/gebco"
+	"github.com/gracefulearth/gopixi"
+)
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to analyse")
+	dstArg := flag.String("dst", "", "Path to the output file")
+	boundsArg := flag.String("bounds", "", "the region to analyse as west,south,east,north in decimal degrees")
+	resolutionArg := flag.Float64("resolution", 0, "the approximate pixel size in degrees to analyse at (0 = full resolution)")
+	surfaceArg := gebco.GebcoDataSubIce
+	flag.TextVar(&surfaceArg, "surface", gebco.GebcoDataSubIce, "the surface (ice, sub-ice) to analyse")
+	vrmRadiusArg := flag.Float64("vrmRadius", 1000, "the radius in metres of the VRM neighbourhood")
+	bpiArg := flag.String("bpi", "1000:5000,5000:25000", "comma separated inner:outer radii in metres of the BPI annuli, one band each")
+	formatArg := flag.String("format", "geotiff", "the output format (geotiff, pixi)")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" || *dstArg == "" || *boundsArg == "" {
+		flag.Usage()
+		return
+	}
+
+	bounds, err := gebco.ParseBounds(*boundsArg)
+	if err != nil {
+		fmt.Printf("invalid bounds argument: %v\n", err)
+		return
+	}
+
+	annuli, err := gebco.ParseAnnuli(*bpiArg)
+	if err != nil {
+		fmt.Printf("invalid bpi argument: %v\n", err)
+		return
+	}
+
+	if *formatArg != "geotiff" && *formatArg != "pixi" {
+		fmt.Printf("invalid format argument: %s\n", *formatArg)
+		return
+	}
+
+	dataset, err := gebco.OpenDataset(*srcArg, 256)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	grid := dataset.GridFor(*resolutionArg)
+	fmt.Printf("Computing roughness over layer %s...\n", grid.Name())
+	raster, err := gebco.RoughnessRaster(grid, bounds, gebco.RoughnessOptions{
+		Surface:   surfaceArg,
+		VrmRadius: *vrmRadiusArg,
+		Bpi:       annuli,
+	})
+	if err != nil {
+		fmt.Printf("failed to compute roughness: %v\n", err)
+		return
+	}
+
+	dstFile, err := os.Create(*dstArg)
+	if err != nil {
+		fmt.Printf("failed to create output file: %v\n", err)
+		return
+	}
+	defer dstFile.Close()
+
+	fmt.Printf("Writing %dx%d raster...\n", raster.Width, raster.Height)
+	if *formatArg == "pixi" {
+		err = raster.WritePixi(dstFile, "gebco_roughness", 512, gopixi.WithCompression(gopixi.CompressionFlate))
+	} else {
+		err = raster.WriteGeoTiff(dstFile)
+	}
+	if err != nil {
+		fmt.Printf("failed to write output: %v\n", err)
+		return
+	}
+}
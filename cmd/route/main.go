@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gracefulearth/gebco"
+)
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to route over")
+	pathArg := flag.String("path", "", "the waypoints of the route as semicolon separated lat,lng pairs (e.g. \"50.1,-5.5;40.7,-74.0\")")
+	dstArg := flag.String("dst", "", "Path to the output GeoJSON route (default standard output)")
+	profileArg := flag.String("profile", "", "Path to an output CSV depth profile along the route (default none)")
+	resolutionArg := flag.Float64("resolution", 0.05, "the approximate pixel size in degrees to route at (0 = full resolution)")
+	corridorArg := flag.Float64("corridor", 0, "the greatest distance in kilometres the route may stray from the great circle of each leg (0 = no limit)")
+	minDepthArg := flag.Float64("minDepth", 200, "the depth in metres above which the seafloor is penalised, e.g. fishing grounds")
+	maxDepthArg := flag.Float64("maxDepth", 7000, "the depth in metres below which the seafloor is penalised")
+	depthPenaltyArg := flag.Float64("depthPenalty", 5, "the cost multiplier for seafloor outside the depth range")
+	maxSlopeArg := flag.Float64("maxSlope", 20, "the steepest seafloor slope in degrees the route may cross (0 = no limit)")
+	slopeWeightArg := flag.Float64("slopeWeight", 0.05, "the additional cost per degree of seafloor slope")
+	tidPenaltyArg := flag.Float64("tidPenalty", 1.1, "the cost multiplier for seafloor never measured directly")
+	maxNodesArg := flag.Int("maxNodes", 0, "the number of pixels each leg may explore before giving up (0 = no limit)")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" || *pathArg == "" {
+		flag.Usage()
+		return
+	}
+
+	waypoints, err := gebco.ParseLatLngs(*pathArg)
+	if err != nil {
+		fmt.Printf("invalid path argument: %v\n", err)
+		return
+	}
+
+	if *depthPenaltyArg < 1 || *tidPenaltyArg < 1 || *slopeWeightArg < 0 {
+		fmt.Printf("invalid cost arguments: penalties must be at least 1 and the slope weight positive\n")
+		return
+	}
+
+	dataset, err := gebco.OpenDataset(*srcArg, 1024)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	grid := dataset.GridFor(*resolutionArg)
+	route, err := gebco.LeastCostRoute(grid, waypoints, gebco.RouteOptions{
+		Cost: gebco.CombineCosts(
+			gebco.DepthCost(*minDepthArg, *maxDepthArg, *depthPenaltyArg),
+			gebco.SlopeCost(*maxSlopeArg, *slopeWeightArg),
+			gebco.TidCost(*tidPenaltyArg),
+		),
+		Corridor: *corridorArg * 1e3,
+		MaxNodes: *maxNodesArg,
+	})
+	if err != nil {
+		fmt.Printf("failed to find route: %v\n", err)
+		return
+	}
+
+	var out io.Writer = os.Stdout
+	if *dstArg != "" {
+		dstFile, err := os.Create(*dstArg)
+		if err != nil {
+			fmt.Printf("failed to create output file: %v\n", err)
+			return
+		}
+		defer dstFile.Close()
+		out = dstFile
+	}
+	if err := gebco.WriteGeoJson(out, []gebco.GeoJsonFeature{route.Feature()}); err != nil {
+		fmt.Printf("failed to write GeoJSON: %v\n", err)
+		return
+	}
+
+	if *profileArg != "" {
+		// sample the profile at full resolution even when routing over an overview
+		samples, err := route.Profile(dataset.Grid())
+		if err != nil {
+			fmt.Printf("failed to sample route profile: %v\n", err)
+			return
+		}
+		profileFile, err := os.Create(*profileArg)
+		if err != nil {
+			fmt.Printf("failed to create profile file: %v\n", err)
+			return
+		}
+		defer profileFile.Close()
+		if err := gebco.WriteProfileCsv(profileFile, samples); err != nil {
+			fmt.Printf("failed to write profile: %v\n", err)
+			return
+		}
+	}
+}
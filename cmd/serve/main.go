@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gracefulearth/gebco"
+)
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to serve")
+	addrArg := flag.String("addr", "localhost:8080", "the address to listen on")
+	baseUrlArg := flag.String("baseUrl", "", "the public URL of the server used in the WMTS capabilities (default http://<addr>)")
+	maxZoomArg := flag.Int("maxZoom", 10, "the maximum zoom level to serve")
+	cacheArg := flag.Int("cache", 256, "the number of decoded Pixi tiles to cache per layer")
+	surfaceArg := gebco.GebcoDataIce
+	flag.TextVar(&surfaceArg, "surface", gebco.GebcoDataIce, "the surface (ice, sub-ice) to serve")
+	hillshadeArg := flag.Bool("hillshade", true, "whether to shade the colour relief tiles with a multidirectional hillshade")
+	cptArg := flag.String("cpt", "", "Path to a GMT .cpt colour palette for the colour relief tiles (default the built-in GEBCO ramp)")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" {
+		flag.Usage()
+		return
+	}
+
+	if surfaceArg == gebco.GebcoDataTypeId {
+		fmt.Printf("invalid surface argument: tid\n")
+		return
+	}
+
+	if *maxZoomArg < 0 || *maxZoomArg > 20 {
+		fmt.Printf("invalid max zoom argument: %d\n", *maxZoomArg)
+		return
+	}
+
+	opts := gebco.RenderOptions{Surface: surfaceArg, Hillshade: *hillshadeArg}
+	if *cptArg != "" {
+		cpt, err := gebco.LoadCpt(*cptArg)
+		if err != nil {
+			fmt.Printf("invalid cpt argument: %v\n", err)
+			return
+		}
+		opts.Palette = cpt
+	}
+
+	baseUrl := *baseUrlArg
+	if baseUrl == "" {
+		baseUrl = "http://" + *addrArg
+	}
+
+	dataset, err := gebco.OpenDataset(*srcArg, *cacheArg)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tiles/{z}/{x}/{y}", tileHandler(*maxZoomArg, func(z, x, y int) (image.Image, error) {
+		return gebco.RenderTile(dataset.GridFor(gebco.TileDegreesPerPixel(z)), z, x, y, opts)
+	}))
+	mux.HandleFunc("GET /terrain/{z}/{x}/{y}", tileHandler(*maxZoomArg, func(z, x, y int) (image.Image, error) {
+		return gebco.TerrainRgbTile(dataset.GridFor(gebco.TileDegreesPerPixel(z)), z, x, y, surfaceArg)
+	}))
+	mux.HandleFunc("GET /wmts/1.0.0/WMTSCapabilities.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(gebco.WmtsCapabilities(baseUrl, *maxZoomArg)))
+	})
+
+	fmt.Printf("Serving colour relief tiles at %s/tiles/{z}/{x}/{y}.png\n", baseUrl)
+	fmt.Printf("Serving Terrain-RGB tiles at %s/terrain/{z}/{x}/{y}.png\n", baseUrl)
+	fmt.Printf("Serving WMTS capabilities at %s/wmts/1.0.0/WMTSCapabilities.xml\n", baseUrl)
+	if err := http.ListenAndServe(*addrArg, mux); err != nil {
+		fmt.Printf("failed to serve: %v\n", err)
+		return
+	}
+}
+
+// tileHandler parses XYZ tile coordinates from the request path and writes the generated tile as a PNG.
+func tileHandler(maxZoom int, generate func(z, x, y int) (image.Image, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		z, zErr := strconv.Atoi(r.PathValue("z"))
+		x, xErr := strconv.Atoi(r.PathValue("x"))
+		y, yErr := strconv.Atoi(strings.TrimSuffix(r.PathValue("y"), ".png"))
+		if zErr != nil || xErr != nil || yErr != nil || z > maxZoom || !gebco.ValidTile(z, x, y) {
+			http.NotFound(w, r)
+			return
+		}
+
+		img, err := generate(z, x, y)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to generate tile: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		var buffer bytes.Buffer
+		if err := png.Encode(&buffer, img); err != nil {
+			http.Error(w, fmt.Sprintf("failed to encode tile: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+		w.Write(buffer.Bytes())
+	}
+}
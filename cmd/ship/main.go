@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gracefulearth/gebco"
+)
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to route over, ideally built with shoal overviews")
+	pathArg := flag.String("path", "", "the waypoints of the route as semicolon separated lat,lng pairs (e.g. \"50.1,-5.5;40.7,-74.0\")")
+	draughtArg := flag.Float64("draught", 0, "the draught of the vessel in metres")
+	clearanceArg := flag.Float64("clearance", 2, "the under-keel clearance in metres to keep beneath the vessel")
+	noGoArg := flag.String("noGo", "", "areas to avoid as rings of semicolon separated lat,lng pairs, separated by | (e.g. \"0,0;0,1;1,1;1,0|5,5;5,6;6,6\")")
+	dstArg := flag.String("dst", "", "Path to the output GeoJSON route (default standard output)")
+	profileArg := flag.String("profile", "", "Path to an output CSV depth profile along the route (default none)")
+	maxNodesArg := flag.Int("maxNodes", 0, "the number of pixels each leg may explore at each resolution before giving up (0 = no limit)")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" || *pathArg == "" || *draughtArg <= 0 {
+		flag.Usage()
+		return
+	}
+
+	waypoints, err := gebco.ParseLatLngs(*pathArg)
+	if err != nil {
+		fmt.Printf("invalid path argument: %v\n", err)
+		return
+	}
+
+	noGo := [][]gebco.LatLng{}
+	for part := range strings.SplitSeq(*noGoArg, "|") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		ring, err := gebco.ParseLatLngs(part)
+		if err != nil {
+			fmt.Printf("invalid noGo argument: %v\n", err)
+			return
+		}
+		if len(ring) < 3 {
+			fmt.Printf("invalid noGo argument: a ring needs at least 3 positions\n")
+			return
+		}
+		if ring[0] != ring[len(ring)-1] {
+			ring = append(ring, ring[0])
+		}
+		noGo = append(noGo, ring)
+	}
+
+	dataset, err := gebco.OpenDataset(*srcArg, 1024)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	route, err := gebco.RouteForDraught(dataset, waypoints, gebco.ShipRouteOptions{
+		Draught:   *draughtArg,
+		Clearance: *clearanceArg,
+		NoGo:      noGo,
+		MaxNodes:  *maxNodesArg,
+	})
+	if err != nil {
+		fmt.Printf("failed to find route: %v\n", err)
+		return
+	}
+
+	var out io.Writer = os.Stdout
+	if *dstArg != "" {
+		dstFile, err := os.Create(*dstArg)
+		if err != nil {
+			fmt.Printf("failed to create output file: %v\n", err)
+			return
+		}
+		defer dstFile.Close()
+		out = dstFile
+	}
+	if err := gebco.WriteGeoJson(out, []gebco.GeoJsonFeature{route.Feature()}); err != nil {
+		fmt.Printf("failed to write GeoJSON: %v\n", err)
+		return
+	}
+
+	if *profileArg != "" {
+		samples, err := route.Profile(dataset.Grid())
+		if err != nil {
+			fmt.Printf("failed to sample route profile: %v\n", err)
+			return
+		}
+		profileFile, err := os.Create(*profileArg)
+		if err != nil {
+			fmt.Printf("failed to create profile file: %v\n", err)
+			return
+		}
+		defer profileFile.Close()
+		if err := gebco.WriteProfileCsv(profileFile, samples); err != nil {
+			fmt.Printf("failed to write profile: %v\n", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gracefulearth/gebco"
+)
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to summarise")
+	dstArg := flag.String("dst", "", "Path to the output file (default standard output)")
+	boundsArg := flag.String("bounds", "", "the region to summarise as west,south,east,north in decimal degrees (default the whole globe, or the latitudes of the polygons)")
+	polygonsArg := flag.String("polygons", "", "Path to a GeoJSON file of polygons to summarise within the region (default none)")
+	resolutionArg := flag.Float64("resolution", 0, "the approximate pixel size in degrees to summarise at (0 = full resolution)")
+	surfaceArg := gebco.GebcoDataIce
+	flag.TextVar(&surfaceArg, "surface", gebco.GebcoDataIce, "the surface (ice, sub-ice) to summarise")
+	binSizeArg := flag.Float64("binSize", 100, "the height in metres of the histogram bins")
+	edgesArg := flag.String("edges", "", "comma separated ascending elevations in metres bounding the histogram bins, instead of a bin size")
+	volumeArg := flag.Bool("volume", false, "whether to integrate the volume of water between the top and bottom levels, with its area and mean, median and maximum depth, instead of the histogram")
+	topArg := flag.Float64("top", 0, "the elevation in metres of the reference level to integrate the volume below")
+	bottomArg := flag.Float64("bottom", math.Inf(-1), "the elevation in metres below which to leave water out of the volume (-Inf = down to the surface)")
+	workersArg := flag.Int("workers", 0, "the number of tiles to read in parallel (0 = one per processor)")
+	formatArg := flag.String("format", "csv", "the output format (csv, json)")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" {
+		flag.Usage()
+		return
+	}
+
+	polygons := []gebco.Polygon{}
+	if *polygonsArg != "" {
+		polygonsFile, err := os.Open(*polygonsArg)
+		if err != nil {
+			fmt.Printf("failed to open polygons file: %v\n", err)
+			return
+		}
+		polygons, err = gebco.ReadGeoJsonPolygons(polygonsFile)
+		polygonsFile.Close()
+		if err != nil {
+			fmt.Printf("invalid polygons argument: %v\n", err)
+			return
+		}
+		if len(polygons) == 0 {
+			fmt.Printf("invalid polygons argument: no polygons in %s\n", *polygonsArg)
+			return
+		}
+	}
+
+	bounds := gebco.GlobalBounds
+	if len(polygons) > 0 {
+		bounds = gebco.PolygonsBounds(polygons)
+	}
+	if *boundsArg != "" {
+		var err error
+		bounds, err = gebco.ParseBounds(*boundsArg)
+		if err != nil {
+			fmt.Printf("invalid bounds argument: %v\n", err)
+			return
+		}
+	}
+
+	edges := []float64{}
+	if *edgesArg != "" {
+		for _, part := range strings.Split(*edgesArg, ",") {
+			edge, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				fmt.Printf("invalid edges argument: %v\n", err)
+				return
+			}
+			edges = append(edges, edge)
+		}
+	}
+
+	if *formatArg != "csv" && *formatArg != "json" {
+		fmt.Printf("invalid format argument: %s\n", *formatArg)
+		return
+	}
+
+	dataset, err := gebco.OpenDataset(*srcArg, 256)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	var out io.Writer = os.Stdout
+	if *dstArg != "" {
+		dstFile, err := os.Create(*dstArg)
+		if err != nil {
+			fmt.Printf("failed to create output file: %v\n", err)
+			return
+		}
+		defer dstFile.Close()
+		out = dstFile
+	}
+
+	if *volumeArg {
+		volume, err := gebco.WaterVolume(dataset.GridFor(*resolutionArg), gebco.VolumeOptions{
+			Region:   bounds,
+			Polygons: polygons,
+			Surface:  surfaceArg,
+			Top:      *topArg,
+			Bottom:   *bottomArg,
+			Workers:  *workersArg,
+		})
+		if err != nil {
+			fmt.Printf("failed to compute volume: %v\n", err)
+			return
+		}
+		if *formatArg == "json" {
+			err = writeJson(out, volume)
+		} else {
+			err = writeVolumeCsv(out, volume)
+		}
+		if err != nil {
+			fmt.Printf("failed to write statistics: %v\n", err)
+		}
+		return
+	}
+
+	curve, err := gebco.Hypsometry(dataset.GridFor(*resolutionArg), gebco.HypsometryOptions{
+		Region:   bounds,
+		Polygons: polygons,
+		Surface:  surfaceArg,
+		BinSize:  *binSizeArg,
+		Edges:    edges,
+		Workers:  *workersArg,
+	})
+	if err != nil {
+		fmt.Printf("failed to compute hypsometry: %v\n", err)
+		return
+	}
+
+	if *formatArg == "json" {
+		err = writeJson(out, curve)
+	} else {
+		err = writeBinsCsv(out, curve.Bins)
+	}
+	if err != nil {
+		fmt.Printf("failed to write statistics: %v\n", err)
+		return
+	}
+}
+
+func writeJson(out io.Writer, value any) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func writeVolumeCsv(out io.Writer, volume *gebco.Volume) error {
+	writer := csv.NewWriter(out)
+	err := writer.Write([]string{"top", "bottom", "pixels", "area-km2", "volume-km3", "mean-depth", "median-depth", "max-depth"})
+	if err != nil {
+		return err
+	}
+	err = writer.Write([]string{
+		strconv.FormatFloat(volume.Top, 'f', -1, 64),
+		strconv.FormatFloat(volume.Bottom, 'f', -1, 64),
+		strconv.Itoa(volume.Pixels),
+		strconv.FormatFloat(volume.Area/1e6, 'f', 1, 64),
+		strconv.FormatFloat(volume.Volume/1e9, 'f', 1, 64),
+		strconv.FormatFloat(volume.MeanDepth, 'f', 1, 64),
+		strconv.FormatFloat(volume.MedianDepth, 'f', -1, 64),
+		strconv.FormatFloat(volume.MaxDepth, 'f', -1, 64),
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeBinsCsv(out io.Writer, bins []gebco.HypsometryBin) error {
+	writer := csv.NewWriter(out)
+	err := writer.Write([]string{"min", "max", "pixels", "area-km2", "area-above-km2", "fraction-above"})
+	if err != nil {
+		return err
+	}
+	for _, bin := range bins {
+		err := writer.Write([]string{
+			strconv.FormatFloat(bin.Min, 'f', -1, 64),
+			strconv.FormatFloat(bin.Max, 'f', -1, 64),
+			strconv.Itoa(bin.Pixels),
+			strconv.FormatFloat(bin.Area/1e6, 'f', 1, 64),
+			strconv.FormatFloat(bin.AreaAbove/1e6, 'f', 1, 64),
+			strconv.FormatFloat(bin.FractionAbove, 'f', 6, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
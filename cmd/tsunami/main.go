@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/gracefulearth/gebco"
+)
+
+func main() {
+	srcArg := flag.String("src", "", "Path or URL of the GEBCO Pixi file to estimate travel times over")
+	sourcesArg := flag.String("sources", "", "the positions the tsunami starts from as semicolon separated lat,lng pairs (e.g. \"38.3,142.4\")")
+	boundsArg := flag.String("bounds", "-180,-90,180,90", "the region to solve over as west,south,east,north in decimal degrees")
+	resolutionArg := flag.Float64("resolution", 0.1, "the approximate pixel size in degrees to solve at (0 = full resolution)")
+	minDepthArg := flag.Float64("minDepth", 10, "the depth in metres that shallower water is treated as")
+	dstArg := flag.String("dst", "", "Path to an output Geotiff of the travel time in hours (default none)")
+	isochronesArg := flag.String("isochrones", "", "Path to an output GeoJSON of isochrones (default none)")
+	intervalArg := flag.Float64("interval", 1, "the interval in hours between isochrones")
+	flag.Parse()
+
+	// validate arguments
+	if *srcArg == "" || *sourcesArg == "" || (*dstArg == "" && *isochronesArg == "") {
+		flag.Usage()
+		return
+	}
+
+	sources, err := gebco.ParseLatLngs(*sourcesArg)
+	if err != nil {
+		fmt.Printf("invalid sources argument: %v\n", err)
+		return
+	}
+
+	bounds, err := gebco.ParseBounds(*boundsArg)
+	if err != nil {
+		fmt.Printf("invalid bounds argument: %v\n", err)
+		return
+	}
+
+	if *intervalArg <= 0 {
+		fmt.Printf("invalid interval argument: %g\n", *intervalArg)
+		return
+	}
+
+	dataset, err := gebco.OpenDataset(*srcArg, 1024)
+	if err != nil {
+		fmt.Printf("failed to open GEBCO dataset: %v\n", err)
+		return
+	}
+	defer dataset.Close()
+
+	times, err := gebco.TsunamiTravelTimes(dataset.GridFor(*resolutionArg), gebco.TravelTimeOptions{
+		Region:   bounds,
+		Sources:  sources,
+		MinDepth: *minDepthArg,
+	})
+	if err != nil {
+		fmt.Printf("failed to estimate travel times: %v\n", err)
+		return
+	}
+
+	if *dstArg != "" {
+		dstFile, err := os.Create(*dstArg)
+		if err != nil {
+			fmt.Printf("failed to create output file: %v\n", err)
+			return
+		}
+		defer dstFile.Close()
+		if err := times.Raster.WriteGeoTiff(dstFile); err != nil {
+			fmt.Printf("failed to write Geotiff: %v\n", err)
+			return
+		}
+	}
+
+	if *isochronesArg != "" {
+		latest := 0.0
+		for _, hours := range times.Raster.Bands[0] {
+			if !math.IsNaN(float64(hours)) {
+				latest = max(latest, float64(hours))
+			}
+		}
+		levels := []float64{}
+		for hours := *intervalArg; hours <= latest; hours += *intervalArg {
+			levels = append(levels, hours)
+		}
+		isochrones := times.Isochrones(levels)
+
+		isochronesFile, err := os.Create(*isochronesArg)
+		if err != nil {
+			fmt.Printf("failed to create isochrones file: %v\n", err)
+			return
+		}
+		defer isochronesFile.Close()
+		features := make([]gebco.GeoJsonFeature, len(isochrones))
+		for i, isochrone := range isochrones {
+			features[i] = isochrone.Feature()
+		}
+		if err := gebco.WriteGeoJson(isochronesFile, features); err != nil {
+			fmt.Printf("failed to write GeoJSON: %v\n", err)
+			return
+		}
+	}
+}
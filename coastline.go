@@ -0,0 +1,70 @@
+package gebco
+
+import (
+	"cmp"
+	"slices"
+)
+
+// ReadLandMask reads whether every pixel of the region lies on land, in row-major order.
+func (r GridRegion) ReadLandMask() ([]bool, error) {
+	samples, err := r.ReadSamples()
+	if err != nil {
+		return nil, err
+	}
+	mask := make([]bool, len(samples))
+	for i, sample := range samples {
+		mask[i] = sample.IsLand()
+	}
+	return mask, nil
+}
+
+// LandPolygonOptions configures LandPolygons.
+type LandPolygonOptions struct {
+	Region  Bounds  // The region to polygonise, expanded to whole pixels of the grid.
+	MinArea float64 // The area in square metres below which islands are dropped.
+}
+
+// LandPolygon is an area of land with any lakes or enclosed seas within it as holes.
+type LandPolygon struct {
+	Polygon
+}
+
+// Feature returns the polygon as a GeoJSON Polygon feature, cut at the antimeridian if it crosses it, with its
+// area in square kilometres as a property.
+func (p LandPolygon) Feature() GeoJsonFeature {
+	return NewGeoJsonFeature(p.Geometry(), map[string]any{
+		"areaKm2": p.Area / 1e6,
+	})
+}
+
+// LandPolygons traces the coastline of a region as the 0 m contour of the ice surface and assembles it into land
+// polygons. The contour is forced through the land mask, so every land pixel centre lies inside a polygon and
+// every sea pixel centre outside, while the bathymetry places the coastline between them. The region is
+// surrounded by sea so that every polygon closes; coastlines running around the globe in a region spanning it are
+// closed through a pole.
+func LandPolygons(grid *Grid, opts LandPolygonOptions) ([]LandPolygon, error) {
+	region, err := grid.Region(opts.Region)
+	if err != nil {
+		return nil, err
+	}
+	samples, err := region.ReadSamples()
+	if err != nil {
+		return nil, err
+	}
+	land := make([]bool, len(samples))
+	values := make([]float32, len(samples))
+	for i, sample := range samples {
+		land[i], values[i] = sample.IsLand(), float32(sample.Ice)
+	}
+
+	polygons := []LandPolygon{}
+	for _, polygon := range polygonise(region, land, values) {
+		if polygon.Area >= opts.MinArea {
+			polygons = append(polygons, LandPolygon{Polygon: polygon})
+		}
+	}
+	slices.SortStableFunc(polygons, func(a, b LandPolygon) int {
+		return cmp.Compare(b.Area, a.Area)
+	})
+	return polygons, nil
+}
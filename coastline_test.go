@@ -0,0 +1,84 @@
+package gebco
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRingArea(t *testing.T) {
+	square := []LatLng{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 1}, {Lat: 1, Lng: 1}, {Lat: 1, Lng: 0}, {Lat: 0, Lng: 0}}
+	expected := EarthRadius * EarthRadius * radians(1) * math.Sin(radians(1))
+	if actual := RingArea(square); math.Abs(actual-expected) > 1 {
+		t.Errorf("expected an anticlockwise area of %f, got %f", expected, actual)
+	}
+	reversed := []LatLng{{Lat: 0, Lng: 0}, {Lat: 1, Lng: 0}, {Lat: 1, Lng: 1}, {Lat: 0, Lng: 1}, {Lat: 0, Lng: 0}}
+	if actual := RingArea(reversed); math.Abs(actual+expected) > 1 {
+		t.Errorf("expected a clockwise area of %f, got %f", -expected, actual)
+	}
+}
+
+func TestLandPolygons(t *testing.T) {
+	// a 5x5 pixel continent with a one pixel lake, a two pixel island across the antimeridian and land along the
+	// southern edge of the grid
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		switch {
+		case x == 12 && y == 5:
+			return GebcoSample{Ice: -20, SubIce: -20, Tid: GebcoTypeSingleBeam}
+		case x >= 10 && x < 15 && y >= 3 && y < 8:
+			return GebcoSample{Ice: 100, SubIce: 100, Tid: GebcoTypeLand}
+		case x == 10 && y == 12:
+			return GebcoSample{Ice: -10, SubIce: -10, Tid: GebcoTypeLand} // land below sea level
+		case (x == 35 || x == 0) && y == 9:
+			return GebcoSample{Ice: 1, SubIce: 1, Tid: GebcoTypeSingleBeam} // above sea level
+		case y == 17:
+			return GebcoSample{Ice: 2000, SubIce: 0, Tid: GebcoTypeLand}
+		}
+		return GebcoSample{Ice: -1000, SubIce: -1000, Tid: GebcoTypeSingleBeam}
+	})
+
+	// the pixel below sea level still counts as land by its TID
+	polygons, err := LandPolygons(dataset.Grid(), LandPolygonOptions{Region: Bounds{West: -90, South: -40, East: -20, North: 70}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(polygons) != 2 || len(polygons[0].Holes) != 1 || len(polygons[1].Holes) != 0 {
+		t.Fatalf("expected the continent with a lake and an island, got %+v", polygons)
+	}
+	if expected := RingArea(polygons[0].Exterior) + RingArea(polygons[0].Holes[0]); math.Abs(polygons[0].Area-expected) > 1 {
+		t.Errorf("expected the lake to be excluded from the area %f, got %f", expected, polygons[0].Area)
+	}
+
+	polygons, err = LandPolygons(dataset.Grid(), LandPolygonOptions{Region: GlobalBounds, MinArea: 1e10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(polygons) != 2 {
+		t.Fatalf("expected the continent and the southern land above the minimum area, got %d polygons", len(polygons))
+	}
+	continent, south := polygons[0], polygons[1]
+	for _, p := range south.Exterior {
+		if p.Lat > -75 {
+			t.Errorf("expected the southern land below 75S, got %v", p)
+		}
+	}
+	if south.Exterior[len(south.Exterior)-2].Lat != -90 {
+		t.Errorf("expected the southern land to close through the pole, got %v", south.Exterior)
+	}
+	if len(continent.Holes) != 1 {
+		t.Errorf("expected the continent to have a lake, got %d holes", len(continent.Holes))
+	}
+	if geometry := south.Feature().Geometry; geometry.Type != "MultiPolygon" {
+		t.Errorf("expected the polar land to be cut at the antimeridian, got %s", geometry.Type)
+	}
+
+	polygons, err = LandPolygons(dataset.Grid(), LandPolygonOptions{Region: Bounds{West: 150, South: -30, East: -150, North: 10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(polygons) != 1 {
+		t.Fatalf("expected the island, got %d polygons", len(polygons))
+	}
+	if geometry := polygons[0].Feature().Geometry; geometry.Type != "MultiPolygon" {
+		t.Errorf("expected the island to be cut at the antimeridian, got %s", geometry.Type)
+	}
+}
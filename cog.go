@@ -0,0 +1,244 @@
+package gebco
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	tagNewSubfileType uint16 = 254
+	tagPredictor      uint16 = 317
+	tagTileWidth      uint16 = 322
+	tagTileLength     uint16 = 323
+	tagTileOffsets    uint16 = 324
+	tagTileByteCounts uint16 = 325
+
+	tiffSubfileReduced      uint16 = 1
+	tiffPredictorHorizontal uint16 = 2
+	tiffSampleFormatUint    uint16 = 1
+	tiffSampleFormatInt     uint16 = 2
+)
+
+// CogOptions configures WriteCog.
+type CogOptions struct {
+	Channel   GebcoDataType // The channel to export, as int16 elevations or uint8 type IDs.
+	Region    Bounds        // The region to export, expanded to whole pixels of the finest grid.
+	TileSize  int           // The width and height of the tiles, a multiple of 16 (0 = 512).
+	Overviews int           // The number of overview levels (-1 = halve until the image fits in one tile).
+	BigTiff   bool          // Whether to write a BigTIFF, which is also used whenever a classic TIFF could overflow.
+}
+
+// cogLevel is one full resolution or overview image of a COG.
+type cogLevel struct {
+	grid          *Grid // the grid sampled for the level
+	factor        int   // the decimation of the finest grid
+	width, height int
+	tilesAcross   int
+	tilesDown     int
+	offsets       []uint64
+	counts        []uint64
+}
+
+// WriteCog writes a region of the dataset as a deflate compressed, tiled Cloud-Optimized GeoTIFF in EPSG:4326
+// with internal overviews. The full resolution image is read from the finest grid of the dataset and each overview
+// from the coarsest grid that still resolves it, by nearest neighbour decimation. Tiles
+// are generated, compressed and written one at a time, so memory use does not depend on the size of the region.
+//
+// Following the COG layout, all IFDs are placed at the start of the file followed by the tile data of the
+// smallest overview first and the full resolution image last. The IFDs have a fixed size, so space is reserved
+// for them and they are written once the tile offsets are known.
+func WriteCog(w io.WriteSeeker, dataset *Dataset, opts CogOptions) error {
+	finest := dataset.Grid()
+	if opts.Channel == GebcoDataTypeId && !finest.HasTid() {
+		return fmt.Errorf("layer %s has no type ID channel to export", finest.Name())
+	}
+	tileSize := opts.TileSize
+	if tileSize == 0 {
+		tileSize = 512
+	}
+	if tileSize < 16 || tileSize%16 != 0 {
+		return fmt.Errorf("invalid tile size %d (must be a positive multiple of 16)", tileSize)
+	}
+
+	region, err := finest.Region(opts.Region)
+	if err != nil {
+		return err
+	}
+	x0, y0, width, height := region.X, region.Y, region.Width, region.Height
+	step := finest.DegreesPerPixel()
+	west, north := region.West(), region.North()
+
+	levelCount := opts.Overviews + 1
+	if opts.Overviews < 0 {
+		levelCount = 1
+		for max(width, height) > tileSize<<(levelCount-1) {
+			levelCount++
+		}
+	}
+	levels := make([]*cogLevel, levelCount)
+	for i := range levels {
+		factor := 1 << i
+		level := &cogLevel{
+			grid:   finest,
+			factor: factor,
+			width:  (width + factor - 1) / factor,
+			height: (height + factor - 1) / factor,
+		}
+		if opts.Channel != GebcoDataTypeId {
+			level.grid = dataset.GridFor(step * float64(factor))
+		}
+		level.tilesAcross = (level.width + tileSize - 1) / tileSize
+		level.tilesDown = (level.height + tileSize - 1) / tileSize
+		level.offsets = make([]uint64, level.tilesAcross*level.tilesDown)
+		level.counts = make([]uint64, level.tilesAcross*level.tilesDown)
+		levels[i] = level
+	}
+
+	bitsPerSample, sampleFormat := uint16(16), tiffSampleFormatInt
+	if opts.Channel == GebcoDataTypeId {
+		bitsPerSample, sampleFormat = 8, tiffSampleFormatUint
+	}
+	// a deflate stream can be slightly larger than its input, so leave a generous margin before switching
+	rawSize := uint64(0)
+	for _, level := range levels {
+		rawSize += uint64(len(level.offsets)) * uint64(tileSize*tileSize) * uint64(bitsPerSample/8)
+	}
+	layout := tiffLayout{big: opts.BigTiff || rawSize > math.MaxUint32/2}
+
+	levelFields := func(i int) []tiffField {
+		level := levels[i]
+		fields := []tiffField{
+			longsField(tagImageWidth, uint32(level.width)),
+			longsField(tagImageLength, uint32(level.height)),
+			shortsField(tagBitsPerSample, bitsPerSample),
+			shortsField(tagCompression, tiffCompressionDeflate),
+			shortsField(tagPhotometricInterpretation, 1),
+			shortsField(tagSamplesPerPixel, 1),
+			shortsField(tagPlanarConfiguration, 1),
+			shortsField(tagPredictor, tiffPredictorHorizontal),
+			longsField(tagTileWidth, uint32(tileSize)),
+			longsField(tagTileLength, uint32(tileSize)),
+			layout.offsetsField(tagTileOffsets, level.offsets),
+			layout.offsetsField(tagTileByteCounts, level.counts),
+			shortsField(tagSampleFormat, sampleFormat),
+		}
+		if i == 0 {
+			fields = append(fields, geoFields(PlateCarree{}, west, north, step, step)...)
+		} else {
+			fields = append(fields, longsField(tagNewSubfileType, uint32(tiffSubfileReduced)))
+		}
+		return fields
+	}
+
+	ifdOffsets := make([]uint64, len(levels)+1)
+	ifdOffsets[0] = layout.headerSize()
+	for i := range levels {
+		ifdOffsets[i+1] = ifdOffsets[i] + layout.ifdSize(levelFields(i))
+	}
+	dataStart := ifdOffsets[len(levels)]
+
+	// write the tiles of the smallest overview first
+	if _, err := w.Seek(int64(dataStart), io.SeekStart); err != nil {
+		return err
+	}
+	buffered := bufio.NewWriterSize(w, 1<<20)
+	offset := dataStart
+	raw := make([]byte, tileSize*tileSize*int(bitsPerSample/8))
+	for i := len(levels) - 1; i >= 0; i-- {
+		level := levels[i]
+		for ty := range level.tilesDown {
+			for tx := range level.tilesAcross {
+				err := level.readTile(raw, x0, y0, tx, ty, tileSize, west, north, step, opts.Channel)
+				if err != nil {
+					return err
+				}
+				tile, err := deflate(raw)
+				if err != nil {
+					return err
+				}
+				if _, err := buffered.Write(tile); err != nil {
+					return err
+				}
+				index := ty*level.tilesAcross + tx
+				level.offsets[index] = offset
+				level.counts[index] = uint64(len(tile))
+				offset += uint64(len(tile))
+			}
+		}
+	}
+	if !layout.big && offset > math.MaxUint32 {
+		return fmt.Errorf("COG of %d bytes is too large for a classic TIFF, use BigTIFF", offset)
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+
+	if _, err := w.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := w.Write(layout.header(ifdOffsets[0])); err != nil {
+		return err
+	}
+	for i := range levels {
+		next := ifdOffsets[i+1]
+		if i == len(levels)-1 {
+			next = 0
+		}
+		if _, err := layout.writeIfd(w, ifdOffsets[i], levelFields(i), next); err != nil {
+			return err
+		}
+	}
+	_, err = w.Seek(int64(offset), io.SeekStart)
+	return err
+}
+
+// readTile fills raw with the horizontally differenced little-endian samples of a tile of the level. The
+// differences of pixels beyond the edge of the image are zero, so once the predictor is undone the pixels beyond
+// the east edge repeat the last pixel of their row and the rows beyond the south edge are zero.
+func (l *cogLevel) readTile(raw []byte, x0, y0, tx, ty, tileSize int, west, north, step float64, channel GebcoDataType) error {
+	sampleSize := len(raw) / (tileSize * tileSize)
+	clear(raw)
+	buf := l.grid.newSampleBuffer()
+	for j := range tileSize {
+		y := ty*tileSize + j
+		if y >= l.height {
+			break
+		}
+		row := raw[j*tileSize*sampleSize : (j+1)*tileSize*sampleSize]
+		previous := int64(0)
+		for i := range tileSize {
+			x := tx*tileSize + i
+			if x >= l.width {
+				break
+			}
+
+			var sample GebcoSample
+			var err error
+			if l.factor == 1 {
+				sample, err = l.grid.sampleInto(x0+x, y0+y, buf)
+			} else {
+				// the finest pixel at the centre of the decimated one
+				px, py := l.grid.Pixel(LatLng{
+					Lat: north - (float64(y*l.factor)+float64(l.factor)/2)*step,
+					Lng: NormalizeLng(west + (float64(x*l.factor)+float64(l.factor)/2)*step),
+				})
+				sample, err = l.grid.sampleInto(px, py, buf)
+			}
+			if err != nil {
+				return err
+			}
+
+			value := int64(sample.Value(channel))
+			if sampleSize == 2 {
+				binary.LittleEndian.PutUint16(row[2*i:], uint16(value-previous))
+			} else {
+				row[i] = uint8(value - previous)
+			}
+			previous = value
+		}
+	}
+	return nil
+}
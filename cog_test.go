@@ -0,0 +1,147 @@
+package gebco
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gracefulearth/go-colorext"
+	"github.com/gracefulearth/image/tiff"
+)
+
+func TestWriteCog(t *testing.T) {
+	dataset := writeTestDataset(t, 64, 16, func(x, y int) GebcoSample {
+		return GebcoSample{Ice: int16(100*y - x), SubIce: int16(-y), Tid: GebcoTypeId(x % 7)}
+	})
+
+	cases := []struct {
+		name           string
+		opts           CogOptions
+		expectedSizes  [][2]int // the width and height of each IFD
+		expectedOrigin [2]int   // the finest grid pixel at the top left
+	}{
+		{"global", CogOptions{Channel: GebcoDataIce, Region: GlobalBounds, TileSize: 16, Overviews: -1}, [][2]int{{64, 32}, {32, 16}, {16, 8}}, [2]int{0, 0}},
+		{"region bigtiff", CogOptions{Channel: GebcoDataSubIce, Region: Bounds{West: 10, South: -20, East: 50, North: 40}, TileSize: 16, Overviews: 1, BigTiff: true}, [][2]int{{8, 12}, {4, 6}}, [2]int{33, 8}},
+		{"type ids", CogOptions{Channel: GebcoDataTypeId, Region: Bounds{West: 170, South: -10, East: -170, North: 10}, TileSize: 16}, [][2]int{{4, 4}}, [2]int{62, 14}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test.tif")
+			file, err := os.Create(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := WriteCog(file, dataset, c.opts); err != nil {
+				t.Fatal(err)
+			}
+			file.Close()
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			big := data[2] == 43
+			if big != c.opts.BigTiff {
+				t.Errorf("expected BigTIFF %v", c.opts.BigTiff)
+			}
+			offset := uint64(binary.LittleEndian.Uint32(data[4:]))
+			if big {
+				offset = binary.LittleEndian.Uint64(data[8:])
+			}
+
+			// every IFD precedes all tile data, and coarser images precede finer ones
+			ifds := []map[uint16][]uint64{}
+			for offset != 0 {
+				fields, next := readTestTiffFields(t, data, offset, big)
+				for _, tileOffset := range fields[tagTileOffsets] {
+					if tileOffset < offset {
+						t.Errorf("expected tile data at %d after IFD at %d", tileOffset, offset)
+					}
+				}
+				if len(ifds) > 0 && fields[tagTileOffsets][0] > ifds[len(ifds)-1][tagTileOffsets][0] {
+					t.Errorf("expected overview %d tiles before those of the finer image", len(ifds))
+				}
+				ifds = append(ifds, fields)
+				offset = next
+			}
+			if len(ifds) != len(c.expectedSizes) {
+				t.Fatalf("expected %d IFDs, got %d", len(c.expectedSizes), len(ifds))
+			}
+			for i, size := range c.expectedSizes {
+				if int(ifds[i][tagImageWidth][0]) != size[0] || int(ifds[i][tagImageLength][0]) != size[1] {
+					t.Errorf("expected IFD %d to be %dx%d, got %dx%d", i, size[0], size[1], ifds[i][tagImageWidth][0], ifds[i][tagImageLength][0])
+				}
+			}
+
+			// undo the deflate compression and horizontal predictor of the first full resolution tile
+			start, count := ifds[0][tagTileOffsets][0], ifds[0][tagTileByteCounts][0]
+			reader, err := zlib.NewReader(bytes.NewReader(data[start : start+count]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tile, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sampleSize := int(ifds[0][tagBitsPerSample][0] / 8)
+			for j := range min(16, c.expectedSizes[0][1]) {
+				value := 0
+				for i := range min(16, c.expectedSizes[0][0]) {
+					if sampleSize == 2 {
+						value = int(int16(uint16(value) + binary.LittleEndian.Uint16(tile[2*(16*j+i):])))
+					} else {
+						value = int(uint8(value) + tile[16*j+i])
+					}
+					expected, err := dataset.Grid().Sample(c.expectedOrigin[0]+i, c.expectedOrigin[1]+j)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if value != int(expected.Value(c.opts.Channel)) {
+						t.Fatalf("expected %v at (%d, %d), got %d", expected.Value(c.opts.Channel), i, j, value)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestWriteCogDecodes(t *testing.T) {
+	dataset := writeTestDataset(t, 64, 16, func(x, y int) GebcoSample {
+		return GebcoSample{Ice: int16(300*y - 7*x - 4000), SubIce: int16(-y)}
+	})
+	path := filepath.Join(t.TempDir(), "test.tif")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteCog(file, dataset, CogOptions{Channel: GebcoDataIce, Region: GlobalBounds, TileSize: 16, Overviews: -1}); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	// the same reader that decodes the source GEBCO GeoTIFFs reads the full resolution image
+	file, err = os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	img, err := tiff.Decode(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 64 || img.Bounds().Dy() != 32 {
+		t.Fatalf("expected a 64x32 image, got %v", img.Bounds())
+	}
+	for y := range 32 {
+		for x := range 64 {
+			expected := int16(300*y - 7*x - 4000)
+			if actual := img.At(x, y).(colorext.GrayS16).Y; actual != expected {
+				t.Fatalf("expected %d at (%d, %d), got %d", expected, x, y, actual)
+			}
+		}
+	}
+}
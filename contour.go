@@ -0,0 +1,183 @@
+package gebco
+
+import (
+	"fmt"
+	"math"
+)
+
+// ContourOptions configures Contours.
+type ContourOptions struct {
+	Surface GebcoDataType // The surface (ice or sub-ice) to contour.
+	Region  Bounds        // The region to contour, expanded to whole pixels of the grid.
+	Levels  []float64     // The elevations in metres to trace, negative for depths.
+}
+
+// Contour is a line of constant elevation. The shallower side of the line is on its left when viewed on a
+// north-up map, so closed contours run anticlockwise around highs and clockwise around deeps.
+type Contour struct {
+	Level  float64  `json:"level"`
+	Closed bool     `json:"closed"` // Whether the line is a ring, in which case its last point repeats its first.
+	Points []LatLng `json:"points"` // Longitudes are unwrapped so consecutive points never differ by more than 180 degrees.
+}
+
+// Feature returns the contour as a GeoJSON LineString feature, split at the antimeridian if it crosses it, with
+// its elevation and depth as properties.
+func (c Contour) Feature() GeoJsonFeature {
+	return NewGeoJsonFeature(LineStringGeometry(c.Points), map[string]any{
+		"elevation": c.Level,
+		"depth":     -c.Level,
+		"closed":    c.Closed,
+	})
+}
+
+// Contours traces contour lines through the pixel centres of a region of a grid with marching squares. The
+// region is read as a whole, so lines continue seamlessly across tile boundaries, and a region spanning the
+// globe wraps across the antimeridian so that lines around it close. Lines that leave the region end at its
+// edge. Ambiguous saddle cells are resolved by the average of their corners, which keeps the contours of
+// different levels from crossing.
+func Contours(grid *Grid, opts ContourOptions) ([]Contour, error) {
+	if opts.Surface == GebcoDataTypeId {
+		return nil, fmt.Errorf("cannot contour type IDs")
+	}
+	region, err := grid.Region(opts.Region)
+	if err != nil {
+		return nil, err
+	}
+	values, err := region.ReadValues(opts.Surface)
+	if err != nil {
+		return nil, err
+	}
+
+	contours := []Contour{}
+	for _, level := range opts.Levels {
+		contours = append(contours, contourLevel(region, values, level)...)
+	}
+	return contours, nil
+}
+
+// contourLevel traces the contours of one level through the values of a region.
+func contourLevel(region GridRegion, values []float32, level float64) []Contour {
+	width, height := region.Width, region.Height
+	cellsAcross := width - 1
+	if region.Wraps() {
+		cellsAcross = width
+	}
+	value := func(i, j int) float64 {
+		return float64(values[region.Index(i%width, j)])
+	}
+	above := func(i, j int) bool {
+		return value(i, j) >= level
+	}
+	// edges between horizontally adjacent pixel centres have even IDs and between vertical neighbours odd IDs
+	horizontalEdge := func(i, j int) int {
+		return 2 * region.Index(i%width, j)
+	}
+	verticalEdge := func(i, j int) int {
+		return 2*region.Index(i%width, j) + 1
+	}
+	edgePosition := func(edge int) LatLng {
+		i, j := (edge/2)%width, (edge/2)/width
+		if edge%2 == 0 {
+			t := (level - value(i, j)) / (value(i+1, j) - value(i, j))
+			return region.Position(float64(i)+0.5+t, float64(j)+0.5)
+		}
+		t := (level - value(i, j)) / (value(i, j+1) - value(i, j))
+		return region.Position(float64(i)+0.5, float64(j)+0.5+t)
+	}
+
+	// each cell contributes segments from an edge where its boundary, walked clockwise, rises through the level
+	// to one where it falls, so neighbouring cells chain end to start along their shared edge
+	next := map[int]int{}
+	ends := map[int]bool{}
+	starts := []int{}
+	type crossing struct {
+		edge   int
+		rising bool
+	}
+	for j := range height - 1 {
+		for i := range cellsAcross {
+			corners := [4][2]int{{i, j}, {i + 1, j}, {i + 1, j + 1}, {i, j + 1}}
+			edges := [4]int{horizontalEdge(i, j), verticalEdge(i+1, j), horizontalEdge(i, j+1), verticalEdge(i, j)}
+			crossings := make([]crossing, 0, 4)
+			for k, edge := range edges {
+				from, to := corners[k], corners[(k+1)%4]
+				fromAbove, toAbove := above(from[0], from[1]), above(to[0], to[1])
+				if fromAbove != toAbove {
+					crossings = append(crossings, crossing{edge: edge, rising: toAbove})
+				}
+			}
+			if len(crossings) == 0 {
+				continue
+			}
+
+			// pair each rising crossing with the next falling one clockwise, which separates the high corners,
+			// or with the previous falling one when the centre of a saddle is high and connects them
+			offset := 1
+			if len(crossings) == 4 {
+				centre := (value(i, j) + value(i+1, j) + value(i+1, j+1) + value(i, j+1)) / 4
+				if centre >= level {
+					offset = len(crossings) - 1
+				}
+			}
+			for k, c := range crossings {
+				if !c.rising {
+					continue
+				}
+				end := crossings[(k+offset)%len(crossings)].edge
+				next[c.edge] = end
+				ends[end] = true
+				starts = append(starts, c.edge)
+			}
+		}
+	}
+
+	contours := []Contour{}
+	trace := func(start int) {
+		contour := Contour{Level: level}
+		edge := start
+		for {
+			position := edgePosition(edge)
+			if n := len(contour.Points); n == 0 {
+				contour.Points = append(contour.Points, position)
+			} else {
+				// unwrap across the antimeridian, and skip repeats where the line passes through a pixel centre
+				previous := contour.Points[n-1]
+				position.Lng += 360 * math.Round((previous.Lng-position.Lng)/360)
+				if position != previous {
+					contour.Points = append(contour.Points, position)
+				}
+			}
+
+			following, ok := next[edge]
+			if !ok {
+				break
+			}
+			delete(next, edge)
+			if following == start {
+				contour.Closed = true
+				first := contour.Points[0]
+				last := contour.Points[len(contour.Points)-1]
+				first.Lng += 360 * math.Round((last.Lng-first.Lng)/360)
+				contour.Points = append(contour.Points, first)
+				break
+			}
+			edge = following
+		}
+		if len(contour.Points) > 1 {
+			contours = append(contours, contour)
+		}
+	}
+
+	// open lines start on the edge of the region, and whatever remains forms rings
+	for _, start := range starts {
+		if _, ok := next[start]; ok && !ends[start] {
+			trace(start)
+		}
+	}
+	for _, start := range starts {
+		if _, ok := next[start]; ok {
+			trace(start)
+		}
+	}
+	return contours
+}
@@ -0,0 +1,105 @@
+package gebco
+
+import (
+	"math"
+	"testing"
+)
+
+// signedArea returns the shoelace area of a ring in square degrees, positive when it runs anticlockwise.
+func signedArea(points []LatLng) float64 {
+	area := 0.0
+	for i := 1; i < len(points); i++ {
+		area += points[i-1].Lng*points[i].Lat - points[i].Lng*points[i-1].Lat
+	}
+	return area / 2
+}
+
+func TestContours(t *testing.T) {
+	// two peaks on diagonally adjacent pixels either side of a tile boundary, in a flat sea
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		if (x == 5 && y == 5) || (x == 6 && y == 6) {
+			return GebcoSample{Ice: 0, SubIce: 0}
+		}
+		return GebcoSample{Ice: -1000, SubIce: -1000}
+	})
+
+	cases := []struct {
+		name          string
+		level         float64
+		expectedRings int
+		expectedArea  float64 // the total signed area of the rings
+	}{
+		{"saddle connected", -500, 1, 2*50 + 2*25},
+		{"saddle separated", -400, 2, 2 * 2 * 0.4 * 0.4 * 100},
+		{"deeper than everything", -2000, 0, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			contours, err := Contours(dataset.Grid(), ContourOptions{
+				Surface: GebcoDataIce,
+				Region:  Bounds{West: -150, South: 0, East: -90, North: 60},
+				Levels:  []float64{c.level},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(contours) != c.expectedRings {
+				t.Fatalf("expected %d contours, got %d", c.expectedRings, len(contours))
+			}
+			area := 0.0
+			for _, contour := range contours {
+				if !contour.Closed || contour.Points[0] != contour.Points[len(contour.Points)-1] {
+					t.Errorf("expected a closed ring, got %v", contour.Points)
+				}
+				area += signedArea(contour.Points)
+			}
+			if math.Abs(area-c.expectedArea) > 1e-6 {
+				t.Errorf("expected an anticlockwise area of %f, got %f", c.expectedArea, area)
+			}
+		})
+	}
+}
+
+func TestContoursWrapAntimeridian(t *testing.T) {
+	// elevation rises northwards, so each contour is a parallel around the globe
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		return GebcoSample{Ice: int16(-100 * y), SubIce: int16(-100 * y)}
+	})
+
+	contours, err := Contours(dataset.Grid(), ContourOptions{
+		Surface: GebcoDataIce,
+		Region:  GlobalBounds,
+		Levels:  []float64{-250},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contours) != 1 {
+		t.Fatalf("expected 1 contour, got %d", len(contours))
+	}
+	contour := contours[0]
+	if !contour.Closed || len(contour.Points) != 37 {
+		t.Fatalf("expected a closed ring of 37 points, got %v", contour.Points)
+	}
+	first, last := contour.Points[0], contour.Points[len(contour.Points)-1]
+	if first.Lat != 60 || last.Lat != 60 || math.Abs(last.Lng-first.Lng) != 360 {
+		t.Errorf("expected a ring around the 60th parallel, got %v to %v", first, last)
+	}
+	// high ground to the north lies on the left, so the ring runs eastwards
+	if contour.Points[1].Lng < first.Lng {
+		t.Errorf("expected the contour to run eastwards, got %v then %v", first, contour.Points[1])
+	}
+
+	geometry := contour.Feature().Geometry
+	parts, ok := geometry.Coordinates.([][][2]float64)
+	if geometry.Type != "MultiLineString" || !ok || len(parts) != 2 {
+		t.Fatalf("expected the ring to be split at the antimeridian, got %+v", geometry)
+	}
+	for _, part := range parts {
+		for _, coordinate := range part {
+			if coordinate[0] < -180 || coordinate[0] > 180 {
+				t.Errorf("expected longitudes within [-180, 180], got %v", coordinate)
+			}
+		}
+	}
+}
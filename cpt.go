@@ -0,0 +1,377 @@
+package gebco
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Palette maps a value to a colour, for use by Render.
+type Palette interface {
+	Color(value float64) color.NRGBA
+}
+
+var _ Palette = ColorRamp{}
+var _ Palette = (*Cpt)(nil)
+
+// CptSlice is a continuous interval of a GMT colour palette, linearly interpolated from LowColor at Low to
+// HighColor at High.
+type CptSlice struct {
+	Low       float64
+	High      float64
+	LowColor  color.NRGBA
+	HighColor color.NRGBA
+	Label     string
+}
+
+// CptCategory is a single key of a categorical GMT colour palette.
+type CptCategory struct {
+	Key   float64
+	Color color.NRGBA
+	Label string
+}
+
+// Cpt is a GMT colour palette table, either continuous (a list of slices) or categorical (a list of keys).
+type Cpt struct {
+	Slices     []CptSlice
+	Categories []CptCategory
+	Background color.NRGBA // The colour of values below the first slice (B).
+	Foreground color.NRGBA // The colour of values above the last slice (F).
+	NaN        color.NRGBA // The colour of NaN values and keys missing from a categorical palette (N).
+}
+
+// Categorical reports whether the palette maps discrete keys rather than continuous intervals.
+func (c *Cpt) Categorical() bool {
+	return len(c.Categories) > 0
+}
+
+// Color returns the colour of a value. Continuous palettes interpolate within the slice containing the value,
+// falling back to the background or foreground colour outside the palette range. A value in a gap between
+// slices takes the low colour of the slice above it. Categorical palettes match the value to a key exactly.
+func (c *Cpt) Color(value float64) color.NRGBA {
+	if math.IsNaN(value) {
+		return c.NaN
+	}
+	if c.Categorical() {
+		for _, category := range c.Categories {
+			if category.Key == value {
+				return category.Color
+			}
+		}
+		return c.NaN
+	}
+	if len(c.Slices) == 0 {
+		return c.NaN
+	}
+	if value < c.Slices[0].Low {
+		return c.Background
+	}
+	for _, slice := range c.Slices {
+		if value <= slice.High {
+			if value <= slice.Low || slice.High == slice.Low {
+				return slice.LowColor
+			}
+			return lerpColor(slice.LowColor, slice.HighColor, (value-slice.Low)/(slice.High-slice.Low))
+		}
+	}
+	return c.Foreground
+}
+
+// LoadCpt reads a GMT colour palette table from a file.
+func LoadCpt(path string) (*Cpt, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CPT file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	cpt, err := ParseCpt(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CPT file %s: %w", path, err)
+	}
+	return cpt, nil
+}
+
+// ParseCpt reads a GMT colour palette table. Colours may be written as r/g/b, r g b, h-s-v (with an HSV colour
+// model), #rrggbb, a single grey level or a basic colour name. Continuous records take the form
+// "z0 color z1 color [;label]" and categorical records "key color [;label]". A record of four plain numbers is
+// read as a continuous grey slice, so categorical RGB colours must use the r/g/b form.
+func ParseCpt(r io.Reader) (*Cpt, error) {
+	cpt := &Cpt{
+		Background: color.NRGBA{A: 0xff},
+		Foreground: color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		NaN:        color.NRGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+	}
+	hsv := false
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber += 1
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if comment, found := strings.CutPrefix(line, "#"); found {
+			key, value, isSetting := strings.Cut(comment, "=")
+			if isSetting && strings.TrimSpace(key) == "COLOR_MODEL" {
+				model := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(value), "+"))
+				switch model {
+				case "rgb":
+					hsv = false
+				case "hsv":
+					hsv = true
+				default:
+					return nil, fmt.Errorf("line %d: unsupported colour model %q", lineNumber, model)
+				}
+			}
+			continue
+		}
+
+		record, label, _ := strings.Cut(line, ";")
+		label = strings.TrimSpace(label)
+		fields := strings.Fields(record)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "B", "F", "N":
+			var c color.NRGBA
+			var err error
+			if values, numErr := parseFloats(fields[1:]); numErr == nil && len(values) == 3 {
+				c = cptComponents(values, hsv)
+			} else if len(fields) == 2 {
+				c, err = parseCptColor(fields[1], hsv)
+			} else {
+				err = fmt.Errorf("expected a single colour")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s colour: %w", lineNumber, fields[0], err)
+			}
+			switch fields[0] {
+			case "B":
+				cpt.Background = c
+			case "F":
+				cpt.Foreground = c
+			case "N":
+				cpt.NaN = c
+			}
+			continue
+		}
+
+		// drop an optional annotation flag (L, U or B) trailing the record
+		if last := fields[len(fields)-1]; len(fields) > 1 && (last == "L" || last == "U" || last == "B") {
+			fields = fields[:len(fields)-1]
+		}
+
+		var err error
+		if values, numErr := parseFloats(fields); numErr == nil {
+			err = cpt.appendNumericRecord(values, label, hsv)
+		} else {
+			err = cpt.appendRecord(fields, label, hsv)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(cpt.Slices) > 0 && len(cpt.Categories) > 0 {
+		return nil, fmt.Errorf("palette mixes continuous and categorical records")
+	}
+	if len(cpt.Slices) == 0 && len(cpt.Categories) == 0 {
+		return nil, fmt.Errorf("palette has no colour records")
+	}
+	return cpt, nil
+}
+
+var cptColorNames = map[string]color.NRGBA{
+	"black":   {A: 0xff},
+	"white":   {R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	"gray":    {R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+	"grey":    {R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+	"red":     {R: 0xff, A: 0xff},
+	"green":   {G: 0xff, A: 0xff},
+	"blue":    {B: 0xff, A: 0xff},
+	"yellow":  {R: 0xff, G: 0xff, A: 0xff},
+	"cyan":    {G: 0xff, B: 0xff, A: 0xff},
+	"magenta": {R: 0xff, B: 0xff, A: 0xff},
+	"orange":  {R: 0xff, G: 0xa5, A: 0xff},
+	"brown":   {R: 0xa5, G: 0x2a, B: 0x2a, A: 0xff},
+}
+
+// appendNumericRecord adds a record written entirely in numbers, one of "key gray", "z0 gray z1 gray" or
+// "z0 r g b z1 r g b".
+func (c *Cpt) appendNumericRecord(values []float64, label string, hsv bool) error {
+	switch len(values) {
+	case 2:
+		c.Categories = append(c.Categories, CptCategory{Key: values[0], Color: gray(values[1]), Label: label})
+		return nil
+	case 4:
+		return c.appendSlice(values[0], gray(values[1]), values[2], gray(values[3]), label)
+	case 8:
+		return c.appendSlice(values[0], cptComponents(values[1:4], hsv), values[4], cptComponents(values[5:8], hsv), label)
+	default:
+		return fmt.Errorf("unexpected record of %d numbers", len(values))
+	}
+}
+
+// appendRecord adds a record whose colours are single tokens: "key color" or "z0 color z1 color".
+func (c *Cpt) appendRecord(fields []string, label string, hsv bool) error {
+	if len(fields) != 2 && len(fields) != 4 {
+		return fmt.Errorf("unexpected record %v", fields)
+	}
+	low, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return fmt.Errorf("invalid value %q", fields[0])
+	}
+	lowColor, err := parseCptColor(fields[1], hsv)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 2 {
+		c.Categories = append(c.Categories, CptCategory{Key: low, Color: lowColor, Label: label})
+		return nil
+	}
+	high, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return fmt.Errorf("invalid value %q", fields[2])
+	}
+	highColor, err := parseCptColor(fields[3], hsv)
+	if err != nil {
+		return err
+	}
+	return c.appendSlice(low, lowColor, high, highColor, label)
+}
+
+func (c *Cpt) appendSlice(low float64, lowColor color.NRGBA, high float64, highColor color.NRGBA, label string) error {
+	if high < low {
+		return fmt.Errorf("slice upper value %g is below lower value %g", high, low)
+	}
+	c.Slices = append(c.Slices, CptSlice{Low: low, High: high, LowColor: lowColor, HighColor: highColor, Label: label})
+	return nil
+}
+
+// parseCptColor parses a colour written as a single token.
+func parseCptColor(token string, hsv bool) (color.NRGBA, error) {
+	if strings.HasPrefix(token, "#") {
+		return ParseHexColor(token)
+	}
+	if c, found := cptColorNames[strings.ToLower(token)]; found {
+		return c, nil
+	}
+
+	separator := "/"
+	if hsv && strings.Count(token, "-") == 2 {
+		separator = "-"
+	}
+	if parts := strings.Split(token, separator); len(parts) == 3 {
+		values, err := parseFloats(parts)
+		if err != nil {
+			return color.NRGBA{}, fmt.Errorf("invalid colour %q", token)
+		}
+		return cptComponents(values, hsv), nil
+	}
+
+	level, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("invalid colour %q", token)
+	}
+	return gray(level), nil
+}
+
+// gray returns the grey colour of a level in [0, 255].
+func gray(level float64) color.NRGBA {
+	v := clampByte(level)
+	return color.NRGBA{R: v, G: v, B: v, A: 0xff}
+}
+
+func parseFloats(parts []string) ([]float64, error) {
+	values := make([]float64, len(parts))
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func clampByte(v float64) uint8 {
+	return uint8(max(0, min(255, math.Round(v))))
+}
+
+// cptComponents converts three colour components to a colour, either RGB in [0, 255] or HSV with hue in
+// degrees and saturation and value in [0, 1].
+func cptComponents(values []float64, hsv bool) color.NRGBA {
+	if !hsv {
+		return color.NRGBA{R: clampByte(values[0]), G: clampByte(values[1]), B: clampByte(values[2]), A: 0xff}
+	}
+	h, s, v := math.Mod(values[0], 360), values[1], values[2]
+	if h < 0 {
+		h += 360
+	}
+	chroma := v * s
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = chroma, x, 0
+	case h < 120:
+		r, g, b = x, chroma, 0
+	case h < 180:
+		r, g, b = 0, chroma, x
+	case h < 240:
+		r, g, b = 0, x, chroma
+	case h < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	m := v - chroma
+	return color.NRGBA{R: clampByte((r + m) * 255), G: clampByte((g + m) * 255), B: clampByte((b + m) * 255), A: 0xff}
+}
+
+// tidCpt colours GEBCO type IDs by group: greens for land, warm colours for direct measurements, blues for
+// indirect measurements and greys for unknown sources.
+const tidCpt = `# GEBCO type identifiers
+0	34/139/34	; Land
+10	255/0/0	; Singlebeam
+11	255/140/0	; Multibeam
+12	255/215/0	; Seismic
+13	255/99/71	; Isolated sounding
+14	205/92/92	; ENC sounding
+15	238/130/238	; Lidar
+16	218/112/214	; Optical
+17	199/21/133	; Combination of direct measurements
+40	0/0/205	; Predicted based on satellite-derived gravity data
+41	100/149/237	; Interpolated
+42	0/191/255	; Digitized contours
+43	70/130/180	; ENC contours
+44	30/144/255	; Multisource satellite-derived gravity
+45	65/105/225	; Flight-derived gravity
+46	135/206/250	; Iceberg draft
+47	176/224/230	; Argo float drift
+70	169/169/169	; Pre-generated grid
+71	211/211/211	; Unknown
+72	128/128/128	; Steering points
+N	0/0/0
+`
+
+// TidPalette is a categorical palette for rendering GEBCO type IDs.
+var TidPalette = func() *Cpt {
+	cpt, err := ParseCpt(strings.NewReader(tidCpt))
+	if err != nil {
+		panic(err)
+	}
+	return cpt
+}()
@@ -0,0 +1,97 @@
+package gebco
+
+import (
+	"image/color"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseCptContinuous(t *testing.T) {
+	cpt, err := ParseCpt(strings.NewReader(`# a test palette
+# COLOR_MODEL = RGB
+-1000	0/0/255	0	0/255/255	L
+0	0 128 0	1000	128 64 0 ; land
+B	black
+F	#ffffff
+N	127
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cpt.Categorical() || len(cpt.Slices) != 2 {
+		t.Fatalf("expected 2 continuous slices, got %+v", cpt)
+	}
+
+	cases := []struct {
+		name     string
+		value    float64
+		expected color.NRGBA
+	}{
+		{"background", -2000, color.NRGBA{A: 255}},
+		{"first slice midpoint", -500, color.NRGBA{R: 0, G: 128, B: 255, A: 255}},
+		{"second slice end", 1000, color.NRGBA{R: 128, G: 64, B: 0, A: 255}},
+		{"foreground", 2000, color.NRGBA{R: 255, G: 255, B: 255, A: 255}},
+		{"nan", math.NaN(), color.NRGBA{R: 127, G: 127, B: 127, A: 255}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := cpt.Color(c.value); actual != c.expected {
+				t.Errorf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+	if cpt.Slices[1].Label != "land" {
+		t.Errorf("expected label land, got %q", cpt.Slices[1].Label)
+	}
+}
+
+func TestCptColorGap(t *testing.T) {
+	cpt, err := ParseCpt(strings.NewReader(`-1000	0/0/255	0	0/255/255
+100	0/128/0	1000	128/64/0
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := color.NRGBA{R: 0, G: 128, B: 0, A: 255}
+	if actual := cpt.Color(50); actual != expected {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestParseCptCategoricalHsv(t *testing.T) {
+	cpt, err := ParseCpt(strings.NewReader(`# COLOR_MODEL = +HSV
+1	0-1-1	; red
+2	120-1-1	; green
+N	0-0-0
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cpt.Categorical() {
+		t.Fatal("expected a categorical palette")
+	}
+	if c := cpt.Color(2); c != (color.NRGBA{G: 255, A: 255}) {
+		t.Errorf("expected green, got %v", c)
+	}
+	if c := cpt.Color(3); c != (color.NRGBA{A: 255}) {
+		t.Errorf("expected NaN colour for missing key, got %v", c)
+	}
+}
+
+func TestParseCptErrors(t *testing.T) {
+	cases := map[string]string{
+		"empty":           "# nothing here\n",
+		"mixed":           "0 red 1 blue\n5 green\n",
+		"reversed slice":  "10 red 0 blue\n",
+		"bad colour":      "0 notacolour 1 blue\n",
+		"unsupported cmy": "# COLOR_MODEL = CMYK\n0 red 1 blue\n",
+	}
+	for name, text := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseCpt(strings.NewReader(text)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
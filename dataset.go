@@ -0,0 +1,352 @@
+package gebco
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gracefulearth/gopixi"
+)
+
+// GebcoSample holds the values stored for a single cell of the GEBCO grid.
+type GebcoSample struct {
+	Ice    int16       `json:"ice"`    // Elevation in metres including surface ice cover.
+	SubIce int16       `json:"subIce"` // Elevation in metres of the ground or seafloor beneath any ice cover.
+	Tid    GebcoTypeId `json:"tid"`    // Source type of the value, GebcoTypeUnknown for layers without a type ID channel.
+}
+
+// Value returns the value of the sample for the given GEBCO data type as a float.
+func (s GebcoSample) Value(data GebcoDataType) float64 {
+	switch data {
+	case GebcoDataIce:
+		return float64(s.Ice)
+	case GebcoDataSubIce:
+		return float64(s.SubIce)
+	case GebcoDataTypeId:
+		return float64(s.Tid)
+	default:
+		panic("unknown GebcoDataType")
+	}
+}
+
+// IsLand reports whether a sample lies on land: either the TID marks it as land data, or its ice surface is above
+// sea level.
+func (s GebcoSample) IsLand() bool {
+	return s.Tid == GebcoTypeLand || s.Ice > 0
+}
+
+// Grid provides access to a global GEBCO Pixi layer by pixel or geographic position. The layer must cover the
+// whole globe in plate carrée with its first dimension running east from 180°W and its second running south
+// from 90°N, as written by cmd/build. Coarser overview layers are supported; their resolution is derived from
+// the layer dimensions.
+type Grid struct {
+	access gopixi.TileAccessLayer
+	width  int
+	height int
+
+	iceChannel    int
+	subIceChannel int
+	tidChannel    int // -1 when the layer has no type ID channel
+}
+
+// NewGrid wraps a Pixi layer accessor as a Grid, checking the layer has the shape and channels of a GEBCO layer.
+func NewGrid(access gopixi.TileAccessLayer) (*Grid, error) {
+	layer := access.Layer()
+	if len(layer.Dimensions) != 2 {
+		return nil, fmt.Errorf("layer %s has %d dimensions, expected 2", layer.Name, len(layer.Dimensions))
+	}
+	grid := &Grid{
+		access:        access,
+		width:         layer.Dimensions[0].Size,
+		height:        layer.Dimensions[1].Size,
+		iceChannel:    layer.Channels.Index("ice"),
+		subIceChannel: layer.Channels.Index("sub-ice"),
+		tidChannel:    layer.Channels.Index("tid"),
+	}
+	if grid.iceChannel < 0 || grid.subIceChannel < 0 {
+		return nil, fmt.Errorf("layer %s is missing ice or sub-ice channels", layer.Name)
+	}
+	if grid.width != 2*grid.height {
+		return nil, fmt.Errorf("layer %s is %dx%d, expected a global grid twice as wide as it is high", layer.Name, grid.width, grid.height)
+	}
+	return grid, nil
+}
+
+func (g *Grid) Name() string {
+	return g.access.Layer().Name
+}
+
+// Width returns the number of pixels along a strip of latitude.
+func (g *Grid) Width() int {
+	return g.width
+}
+
+// Height returns the number of pixels along a strip of longitude.
+func (g *Grid) Height() int {
+	return g.height
+}
+
+// HasTid reports whether the layer stores the GEBCO type ID of each pixel.
+func (g *Grid) HasTid() bool {
+	return g.tidChannel >= 0
+}
+
+// DegreesPerPixel returns the angular spacing between neighbouring pixel centres.
+func (g *Grid) DegreesPerPixel() float64 {
+	return 360.0 / float64(g.width)
+}
+
+// PixelSpacing returns the north-south distance in metres between neighbouring pixel centres.
+func (g *Grid) PixelSpacing() float64 {
+	return EarthRadius * radians(g.DegreesPerPixel())
+}
+
+// PixelCenter returns the position of the centre of a pixel.
+func (g *Grid) PixelCenter(x, y int) LatLng {
+	step := g.DegreesPerPixel()
+	return LatLng{
+		Lat: 90 - (float64(y)+0.5)*step,
+		Lng: -180 + (float64(x)+0.5)*step,
+	}
+}
+
+// Pixel returns the pixel containing a position, wrapping longitude and clamping latitude onto the grid.
+func (g *Grid) Pixel(p LatLng) (x, y int) {
+	fx, fy := g.pixelCoordinate(p)
+	return g.wrapX(int(math.Floor(fx))), g.clampY(int(math.Floor(fy)))
+}
+
+// pixelCoordinate returns the continuous pixel coordinate of a position, where pixel (x, y) covers [x, x+1).
+func (g *Grid) pixelCoordinate(p LatLng) (fx, fy float64) {
+	step := g.DegreesPerPixel()
+	return (NormalizeLng(p.Lng) + 180) / step, (90 - p.Lat) / step
+}
+
+func (g *Grid) wrapX(x int) int {
+	x %= g.width
+	if x < 0 {
+		x += g.width
+	}
+	return x
+}
+
+func (g *Grid) clampY(y int) int {
+	return max(0, min(g.height-1, y))
+}
+
+// acrossPole maps a pixel coordinate that has stepped past a pole back onto the grid, landing on the pixel on
+// the opposite meridian that is geographically adjacent.
+func (g *Grid) acrossPole(x, y int) (int, int) {
+	if y < 0 {
+		return g.wrapX(x + g.width/2), -1 - y
+	}
+	if y >= g.height {
+		return g.wrapX(x + g.width/2), 2*g.height - 1 - y
+	}
+	return g.wrapX(x), y
+}
+
+// Sample returns the values of a pixel. The x coordinate wraps around the antimeridian and the y coordinate is
+// clamped to the poles.
+func (g *Grid) Sample(x, y int) (GebcoSample, error) {
+	return g.sampleInto(x, y, g.newSampleBuffer())
+}
+
+// newSampleBuffer makes a buffer for sampleInto to read the channels of a pixel through.
+func (g *Grid) newSampleBuffer() gopixi.Sample {
+	return make(gopixi.Sample, len(g.access.Layer().Channels))
+}
+
+// sampleInto is Sample reading the channels through a buffer made by newSampleBuffer, so that loops over many
+// pixels reuse one buffer instead of allocating one per pixel. The buffer must not be shared between goroutines.
+func (g *Grid) sampleInto(x, y int, sample gopixi.Sample) (GebcoSample, error) {
+	x, y = g.wrapX(x), g.clampY(y)
+	if err := gopixi.SampleInto(g.access, gopixi.SampleCoordinate{x, y}, sample); err != nil {
+		return GebcoSample{}, fmt.Errorf("failed to read %s pixel (%d,%d): %w", g.Name(), x, y, err)
+	}
+	result := GebcoSample{
+		Ice:    sample[g.iceChannel].(int16),
+		SubIce: sample[g.subIceChannel].(int16),
+		Tid:    GebcoTypeUnknown,
+	}
+	if g.tidChannel >= 0 {
+		result.Tid = GebcoTypeId(sample[g.tidChannel].(uint8))
+	}
+	return result, nil
+}
+
+// SampleAt returns the values of the pixel containing a position.
+func (g *Grid) SampleAt(p LatLng) (GebcoSample, error) {
+	x, y := g.Pixel(p)
+	return g.Sample(x, y)
+}
+
+// ShoalLayerPrefix starts the names of overview layers holding the highest elevation, and so the shoalest depth,
+// of the pixels each of their pixels covers rather than the mean. They are kept apart from the other grids of a
+// dataset so that they are only read where a conservative depth is wanted.
+const ShoalLayerPrefix = "gebco_shoal"
+
+// Dataset is a GEBCO Pixi file opened for reading, giving access to the full resolution layer and any
+// overview layers it contains.
+type Dataset struct {
+	closers []io.Closer
+	pixi    *gopixi.Pixi
+	grids   []*Grid // ordered from finest to coarsest
+
+	shoalGrids []*Grid // ordered from finest to coarsest
+	iceGrid    *IceGrid
+
+	years     []int   // the releases stacked in the year layer, oldest first
+	yearGrids []*Grid // the full resolution grid of each stacked release, in the order of years
+}
+
+// OpenDataset opens a GEBCO Pixi file from a local path or HTTP URL. Every GEBCO layer in the file is given its
+// own handle and a cache holding up to cacheTiles decoded tiles (per year for a layer stacking several releases),
+// so grids can be read concurrently and concurrent readers share decoded tiles.
+func OpenDataset(path string, cacheTiles int) (*Dataset, error) {
+	file, err := gopixi.OpenFileOrHttp(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Pixi file %s: %w", path, err)
+	}
+	dataset := &Dataset{closers: []io.Closer{file}}
+
+	dataset.pixi, err = gopixi.ReadPixi(file)
+	if err != nil {
+		dataset.Close()
+		return nil, fmt.Errorf("failed to read Pixi file %s: %w", path, err)
+	}
+
+	years, err := ParseYears(dataset.pixi.AllTags()["years"])
+	if err != nil {
+		dataset.Close()
+		return nil, fmt.Errorf("failed to read years tag of Pixi file %s: %w", path, err)
+	}
+
+	for _, layer := range dataset.pixi.Layers {
+		if layer.Name == IceLayerName {
+			layerFile, err := gopixi.OpenFileOrHttp(path)
+			if err != nil {
+				dataset.Close()
+				return nil, fmt.Errorf("failed to open Pixi file %s: %w", path, err)
+			}
+			dataset.closers = append(dataset.closers, layerFile)
+			dataset.iceGrid, err = NewIceGrid(newLruTileCache(layerFile, dataset.pixi.Header, layer, cacheTiles))
+			if err != nil {
+				dataset.Close()
+				return nil, err
+			}
+			continue
+		}
+
+		stacked := len(layer.Dimensions) == 3 && layer.Dimensions[2].Name == "year"
+		if layer.Channels.Index("ice") < 0 || layer.Channels.Index("sub-ice") < 0 || (len(layer.Dimensions) != 2 && !stacked) {
+			continue
+		}
+		if stacked && (layer.Dimensions[2].Size != len(years) || layer.Dimensions[2].TileSize != 1) {
+			dataset.Close()
+			return nil, fmt.Errorf("layer %s stacks %d years with tile size %d, expected the %d tagged years with tile size 1", layer.Name, layer.Dimensions[2].Size, layer.Dimensions[2].TileSize, len(years))
+		}
+		layerFile, err := gopixi.OpenFileOrHttp(path)
+		if err != nil {
+			dataset.Close()
+			return nil, fmt.Errorf("failed to open Pixi file %s: %w", path, err)
+		}
+		dataset.closers = append(dataset.closers, layerFile)
+
+		if stacked {
+			stack := newLruTileCache(layerFile, dataset.pixi.Header, layer, cacheTiles*len(years))
+			for i, year := range years {
+				grid, err := NewGrid(newYearSlice(stack, i, year))
+				if err != nil {
+					dataset.Close()
+					return nil, err
+				}
+				dataset.yearGrids = append(dataset.yearGrids, grid)
+			}
+			dataset.years = years
+			continue
+		}
+
+		grid, err := NewGrid(newLruTileCache(layerFile, dataset.pixi.Header, layer, cacheTiles))
+		if err != nil {
+			dataset.Close()
+			return nil, err
+		}
+		if strings.HasPrefix(layer.Name, ShoalLayerPrefix) {
+			dataset.shoalGrids = append(dataset.shoalGrids, grid)
+		} else {
+			dataset.grids = append(dataset.grids, grid)
+		}
+	}
+	if len(dataset.grids) == 0 {
+		dataset.Close()
+		return nil, fmt.Errorf("Pixi file %s contains no GEBCO layers", path)
+	}
+	for _, grids := range [][]*Grid{dataset.grids, dataset.shoalGrids} {
+		slices.SortStableFunc(grids, func(a, b *Grid) int {
+			return b.width - a.width
+		})
+	}
+
+	return dataset, nil
+}
+
+func (d *Dataset) Close() error {
+	var firstErr error
+	for _, closer := range d.closers {
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Pixi returns the metadata of the underlying Pixi file.
+func (d *Dataset) Pixi() *gopixi.Pixi {
+	return d.pixi
+}
+
+// Year returns the GEBCO release year recorded in the file tags, or 0 if there is none.
+func (d *Dataset) Year() int {
+	year, _ := strconv.Atoi(d.pixi.AllTags()["year"])
+	return year
+}
+
+// Grid returns the finest resolution GEBCO layer in the dataset.
+func (d *Dataset) Grid() *Grid {
+	return d.grids[0]
+}
+
+// Grids returns all GEBCO layers in the dataset ordered from finest to coarsest.
+func (d *Dataset) Grids() []*Grid {
+	return d.grids
+}
+
+// ShoalGrids returns the overview layers holding the shoalest depth of each pixel, ordered from finest to
+// coarsest.
+func (d *Dataset) ShoalGrids() []*Grid {
+	return d.shoalGrids
+}
+
+// IceGrid returns the layer of the ice geometry of every pixel of the full resolution layer, which cmd/build adds
+// with -ice.
+func (d *Dataset) IceGrid() (*IceGrid, error) {
+	if d.iceGrid == nil {
+		return nil, fmt.Errorf("dataset has no %s layer", IceLayerName)
+	}
+	return d.iceGrid, nil
+}
+
+// GridFor returns the coarsest layer whose pixels are no larger than degreesPerPixel, or the finest layer when
+// none is fine enough.
+func (d *Dataset) GridFor(degreesPerPixel float64) *Grid {
+	for i := len(d.grids) - 1; i >= 0; i-- {
+		if d.grids[i].DegreesPerPixel() <= degreesPerPixel {
+			return d.grids[i]
+		}
+	}
+	return d.grids[0]
+}
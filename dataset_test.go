@@ -0,0 +1,126 @@
+package gebco
+
+import (
+	"encoding/binary"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gracefulearth/gopixi"
+)
+
+// testLayer is a layer for writeTestDataset to write after the gebco layer, with the samples made by sample and
+// any tags the layer needs added to those of the file.
+type testLayer struct {
+	layer  gopixi.Layer
+	sample func(coord gopixi.SampleCoordinate) gopixi.Sample
+	tags   map[string]string
+}
+
+// writeTestDataset writes a small global GEBCO Pixi file with a "gebco" layer of the given width, filled from
+// value, followed by any extra layers, and returns the opened dataset.
+func writeTestDataset(t *testing.T, width int, tileSize int, value func(x, y int) GebcoSample, extra ...testLayer) *Dataset {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.pixi")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	summary := &gopixi.Pixi{Header: gopixi.NewHeader(binary.LittleEndian, gopixi.OffsetSize8)}
+	if err := summary.Header.WriteHeader(file); err != nil {
+		t.Fatal(err)
+	}
+	tags := map[string]string{"year": "2025"}
+	for _, l := range extra {
+		maps.Copy(tags, l.tags)
+	}
+	if err := summary.AppendTags(file, tags); err != nil {
+		t.Fatal(err)
+	}
+
+	layer := gopixi.NewLayer("gebco",
+		gopixi.DimensionSet{
+			{Name: "lng", TileSize: tileSize, Size: width},
+			{Name: "lat", TileSize: tileSize, Size: width / 2}},
+		gopixi.ChannelSet{
+			{Name: "ice", Type: gopixi.ChannelInt16},
+			{Name: "sub-ice", Type: gopixi.ChannelInt16},
+			{Name: "tid", Type: gopixi.ChannelUint8}},
+		gopixi.WithCompression(gopixi.CompressionFlate),
+	)
+	iterator := gopixi.NewTileOrderWriteIterator(file, summary.Header, layer)
+	err = summary.AppendIterativeLayer(file, layer, iterator, func(dst gopixi.IterativeLayerWriter) error {
+		for dst.Next() {
+			coord := dst.Coordinate()
+			if coord[0] >= width || coord[1] >= width/2 {
+				dst.SetSample(gopixi.Sample{int16(0), int16(0), uint8(0)})
+				continue
+			}
+			sample := value(coord[0], coord[1])
+			dst.SetSample(gopixi.Sample{sample.Ice, sample.SubIce, uint8(sample.Tid)})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, l := range extra {
+		iterator := gopixi.NewTileOrderWriteIterator(file, summary.Header, l.layer)
+		err = summary.AppendIterativeLayer(file, l.layer, iterator, func(dst gopixi.IterativeLayerWriter) error {
+			for dst.Next() {
+				dst.SetSample(l.sample(dst.Coordinate()))
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dataset, err := OpenDataset(path, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dataset.Close() })
+	return dataset
+}
+
+func TestDatasetSample(t *testing.T) {
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		return GebcoSample{Ice: int16(x), SubIce: int16(-y), Tid: GebcoTypeMultiBeam}
+	})
+	if dataset.Year() != 2025 {
+		t.Errorf("expected year 2025, got %d", dataset.Year())
+	}
+
+	grid := dataset.Grid()
+	if grid.Width() != 36 || grid.Height() != 18 || !grid.HasTid() {
+		t.Fatalf("unexpected grid shape %dx%d (tid %v)", grid.Width(), grid.Height(), grid.HasTid())
+	}
+
+	cases := []struct {
+		name     string
+		position LatLng
+		expected GebcoSample
+	}{
+		{"north west corner", LatLng{Lat: 89, Lng: -179}, GebcoSample{Ice: 0, SubIce: 0, Tid: GebcoTypeMultiBeam}},
+		{"south east corner", LatLng{Lat: -89, Lng: 179}, GebcoSample{Ice: 35, SubIce: -17, Tid: GebcoTypeMultiBeam}},
+		{"wrapped longitude", LatLng{Lat: 0.5, Lng: 185}, GebcoSample{Ice: 0, SubIce: -8, Tid: GebcoTypeMultiBeam}},
+		{"north pole", LatLng{Lat: 90, Lng: 0}, GebcoSample{Ice: 18, SubIce: 0, Tid: GebcoTypeMultiBeam}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := grid.SampleAt(c.position)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != c.expected {
+				t.Errorf("expected %+v, got %+v", c.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package gebco
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// DiffOptions configures DiffReleases.
+type DiffOptions struct {
+	Surface  GebcoDataType // The surface (ice, sub-ice) to difference.
+	Region   Bounds        // The region to compare, expanded to whole pixels of the grids.
+	CellSize float64       // The size in degrees of the cells that the changes are summarised over.
+}
+
+// TidTransition counts the pixels whose type ID changed from one source type to another between releases.
+type TidTransition struct {
+	From   GebcoTypeId `json:"from"`
+	To     GebcoTypeId `json:"to"`
+	Pixels int         `json:"pixels"`
+}
+
+// TidTransitionCode encodes a change of type ID as a single value, the old type ID followed by the new one as two
+// decimal digits (e.g. 4111 for interpolated to multibeam).
+func TidTransitionCode(from, to GebcoTypeId) int {
+	return 100*int(from) + int(to)
+}
+
+// DiffCell summarises the changes between two releases within a cell of the compared region.
+type DiffCell struct {
+	Bounds             Bounds  `json:"bounds"`
+	Pixels             int     `json:"pixels"`
+	ChangedPixels      int     `json:"changedPixels"`      // Pixels whose surface elevation changed.
+	MeanChange         float64 `json:"meanChange"`         // The mean change in metres, positive where the surface rose.
+	MaxChange          float64 `json:"maxChange"`          // The largest absolute change in metres.
+	SurveyedPixels     int     `json:"surveyedPixels"`     // Pixels measured directly in the newer release but not the older.
+	SurveyedArea       float64 `json:"surveyedArea"`       // The area of the newly surveyed pixels in square metres.
+	SurveyedMeanChange float64 `json:"surveyedMeanChange"` // The mean absolute change in metres of the newly surveyed pixels.
+}
+
+// ReleaseDiff is the comparison of a region between two GEBCO releases.
+type ReleaseDiff struct {
+	// Raster is the compared region in plate carrée with a "difference" band of the newer surface minus the older
+	// in metres, and a "tid-transition" band holding the TidTransitionCode of pixels whose type ID changed.
+	Raster      *Raster         `json:"-"`
+	Total       DiffCell        `json:"total"`
+	Cells       []DiffCell      `json:"cells"`       // Ordered from north to south, then west to east.
+	Transitions []TidTransition `json:"transitions"` // Ordered from the most pixels to the fewest.
+}
+
+// diffAccumulator gathers the sums behind the means of a DiffCell.
+type diffAccumulator struct {
+	cell              DiffCell
+	changeSum         float64
+	surveyedChangeSum float64
+}
+
+func (a *diffAccumulator) add(change float64, surveyed bool, area float64) {
+	a.cell.Pixels++
+	a.changeSum += change
+	if change != 0 {
+		a.cell.ChangedPixels++
+	}
+	a.cell.MaxChange = max(a.cell.MaxChange, math.Abs(change))
+	if surveyed {
+		a.cell.SurveyedPixels++
+		a.cell.SurveyedArea += area
+		a.surveyedChangeSum += math.Abs(change)
+	}
+}
+
+func (a *diffAccumulator) result() DiffCell {
+	cell := a.cell
+	if cell.Pixels > 0 {
+		cell.MeanChange = a.changeSum / float64(cell.Pixels)
+	}
+	if cell.SurveyedPixels > 0 {
+		cell.SurveyedMeanChange = a.surveyedChangeSum / float64(cell.SurveyedPixels)
+	}
+	return cell
+}
+
+// DiffReleases compares a region of two releases of the same resolution pixel by pixel, differencing a surface,
+// recording where the source type ID changed and summarising the changes over cells of a fixed size in degrees.
+// A pixel counts as newly surveyed when the newer release measures its depth directly but the older did not.
+func DiffReleases(older, newer *Grid, opts DiffOptions) (*ReleaseDiff, error) {
+	if opts.Surface == GebcoDataTypeId {
+		return nil, fmt.Errorf("cannot difference type IDs")
+	}
+	if older.Width() != newer.Width() {
+		return nil, fmt.Errorf("layers %s and %s have different resolutions (%d and %d pixels wide)", older.Name(), newer.Name(), older.Width(), newer.Width())
+	}
+	if !older.HasTid() || !newer.HasTid() {
+		return nil, fmt.Errorf("layers %s and %s must both have type ID channels", older.Name(), newer.Name())
+	}
+	if opts.CellSize <= 0 {
+		return nil, fmt.Errorf("invalid cell size %g", opts.CellSize)
+	}
+	region, err := newer.Region(opts.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	step := newer.DegreesPerPixel()
+	raster := NewRaster(PlateCarree{}, region.West(), region.North(), step, step, region.Width, region.Height, "difference", "tid-transition")
+	total := &diffAccumulator{cell: DiffCell{Bounds: opts.Region}}
+	cells := map[[2]int]*diffAccumulator{}
+	transitions := map[[2]GebcoTypeId]int{}
+	// read tile by tile, grouped by GEBCO tile so that a release read from tiff files loads each of its tiles once
+	gebcoTileSize := newer.Width() / TilesX
+	parts := region.Tiles()
+	slices.SortStableFunc(parts, func(a, b GridRegion) int {
+		return cmp.Or(cmp.Compare(a.Y/gebcoTileSize, b.Y/gebcoTileSize), cmp.Compare(newer.wrapX(a.X)/gebcoTileSize, newer.wrapX(b.X)/gebcoTileSize))
+	})
+	for _, part := range parts {
+		olderPart := part
+		olderPart.Grid = older
+		befores, err := olderPart.ReadSamples()
+		if err != nil {
+			return nil, err
+		}
+		afters, err := part.ReadSamples()
+		if err != nil {
+			return nil, err
+		}
+		for j := range part.Height {
+			for i := range part.Width {
+				before, after := befores[part.Index(i, j)], afters[part.Index(i, j)]
+				pixel := region.Index(part.X-region.X+i, part.Y-region.Y+j)
+				change := after.Value(opts.Surface) - before.Value(opts.Surface)
+				raster.Bands[0][pixel] = float32(change)
+				if before.Tid != after.Tid {
+					raster.Bands[1][pixel] = float32(TidTransitionCode(before.Tid, after.Tid))
+					transitions[[2]GebcoTypeId{before.Tid, after.Tid}]++
+				}
+
+				center := newer.PixelCenter(newer.wrapX(part.X+i), part.Y+j)
+				key, bounds := summaryCell(center, opts.CellSize)
+				cell, ok := cells[key]
+				if !ok {
+					cell = &diffAccumulator{cell: DiffCell{Bounds: bounds}}
+					cells[key] = cell
+				}
+				surveyed := !before.Tid.IsDirect() && after.Tid.IsDirect()
+				area := CellArea(center.Lat, step)
+				cell.add(change, surveyed, area)
+				total.add(change, surveyed, area)
+			}
+		}
+	}
+
+	diff := &ReleaseDiff{Raster: raster, Total: total.result(), Cells: []DiffCell{}, Transitions: []TidTransition{}}
+	for _, cell := range cells {
+		diff.Cells = append(diff.Cells, cell.result())
+	}
+	slices.SortFunc(diff.Cells, func(a, b DiffCell) int {
+		return cmp.Or(cmp.Compare(b.Bounds.North, a.Bounds.North), cmp.Compare(a.Bounds.West, b.Bounds.West))
+	})
+	for key, pixels := range transitions {
+		diff.Transitions = append(diff.Transitions, TidTransition{From: key[0], To: key[1], Pixels: pixels})
+	}
+	slices.SortFunc(diff.Transitions, func(a, b TidTransition) int {
+		return cmp.Or(cmp.Compare(b.Pixels, a.Pixels), cmp.Compare(a.From, b.From), cmp.Compare(a.To, b.To))
+	})
+	return diff, nil
+}
+
+// summaryCell returns the column and row of the cell of a fixed size in degrees, counted from 180°W and 90°N, that
+// contains a position, and the bounds of that cell.
+func summaryCell(p LatLng, size float64) ([2]int, Bounds) {
+	key := [2]int{int(math.Floor((p.Lng + 180) / size)), int(math.Floor((90 - p.Lat) / size))}
+	west, north := -180+float64(key[0])*size, 90-float64(key[1])*size
+	return key, Bounds{West: west, South: max(-90, north-size), East: min(180, west+size), North: north}
+}
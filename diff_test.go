@@ -0,0 +1,73 @@
+package gebco
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestDiffReleases(t *testing.T) {
+	// between releases the south west quarter of the globe is surveyed by multibeam and found 10 m deeper
+	value := func(x, y, year int) GebcoSample {
+		if year == 2025 && x < 18 && y >= 9 {
+			return GebcoSample{Ice: -1010, SubIce: -1010, Tid: GebcoTypeMultiBeam}
+		}
+		return GebcoSample{Ice: -1000, SubIce: -1000, Tid: GebcoTypeInterpolated}
+	}
+	latest := func(x, y int) GebcoSample { return value(x, y, 2025) }
+	dataset := writeTestDataset(t, 36, 6, latest, yearsTestLayer(36, 6, []int{2024, 2025}, nil, value))
+	older, err := dataset.YearGrid(2024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newer, err := dataset.YearGrid(2025)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := DiffReleases(older, newer, DiffOptions{Surface: GebcoDataIce, Region: GlobalBounds, CellSize: 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff.Total.Pixels != 36*18 || diff.Total.ChangedPixels != 18*9 || diff.Total.SurveyedPixels != 18*9 {
+		t.Errorf("unexpected totals %+v", diff.Total)
+	}
+	if math.Abs(diff.Total.MeanChange+2.5) > 1e-9 {
+		t.Errorf("expected mean change -2.5, got %f", diff.Total.MeanChange)
+	}
+	// a quarter of the globe's area is newly surveyed
+	quarter := math.Pi * EarthRadius * EarthRadius
+	if math.Abs(diff.Total.SurveyedArea-quarter)/quarter > 0.01 {
+		t.Errorf("expected surveyed area %g, got %g", quarter, diff.Total.SurveyedArea)
+	}
+
+	if len(diff.Cells) != 8 {
+		t.Fatalf("expected 8 cells, got %d", len(diff.Cells))
+	}
+	for _, cell := range diff.Cells {
+		surveyed := cell.Bounds.West < 0 && cell.Bounds.North <= 0
+		if surveyed != (cell.SurveyedPixels == cell.Pixels) || (cell.SurveyedPixels != 0 && !surveyed) {
+			t.Errorf("unexpected survey of cell %+v", cell)
+		}
+		if surveyed && (cell.MaxChange != 10 || cell.SurveyedMeanChange != 10 || cell.MeanChange != -10) {
+			t.Errorf("unexpected change in surveyed cell %+v", cell)
+		}
+	}
+	if diff.Cells[0].Bounds != (Bounds{West: -180, South: 0, East: -90, North: 90}) {
+		t.Errorf("expected the north west cell first, got %+v", diff.Cells[0].Bounds)
+	}
+
+	expectedTransitions := []TidTransition{{From: GebcoTypeInterpolated, To: GebcoTypeMultiBeam, Pixels: 18 * 9}}
+	if !slices.Equal(diff.Transitions, expectedTransitions) {
+		t.Errorf("expected transitions %+v, got %+v", expectedTransitions, diff.Transitions)
+	}
+
+	index := 12*36 + 5
+	if diff.Raster.Bands[0][index] != -10 || diff.Raster.Bands[1][index] != 4111 {
+		t.Errorf("unexpected surveyed pixel difference %f and transition %f", diff.Raster.Bands[0][index], diff.Raster.Bands[1][index])
+	}
+	if diff.Raster.Bands[0][5] != 0 || !math.IsNaN(float64(diff.Raster.Bands[1][5])) {
+		t.Errorf("unexpected unchanged pixel difference %f and transition %f", diff.Raster.Bands[0][5], diff.Raster.Bands[1][5])
+	}
+}
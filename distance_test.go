@@ -0,0 +1,64 @@
+package gebco
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDistanceTransform(t *testing.T) {
+	width := 72
+	pixelCenter := func(i int) LatLng {
+		return LatLng{Lat: 90 - (float64(i/width)+0.5)*5, Lng: -180 + (float64(i%width)+0.5)*5}
+	}
+
+	cases := []struct {
+		name  string
+		sites func(x, y int) bool
+	}{
+		{"single site", func(x, y int) bool { return x == 3 && y == 20 }},
+		{"across the antimeridian", func(x, y int) bool { return x == 71 && y == 10 }},
+		{"near a pole", func(x, y int) bool { return x == 10 && y == 0 }},
+		{"scattered", func(x, y int) bool { return rand.New(rand.NewSource(int64(x*1000+y))).Float64() < 0.02 }},
+		{"no sites", func(x, y int) bool { return false }},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sites := make([]bool, width*width/2)
+			for i := range sites {
+				sites[i] = c.sites(i%width, i/width)
+			}
+			distances := DistanceTransform(sites, width)
+			spacing := EarthRadius * radians(5)
+
+			off := 0
+			for i := range sites {
+				expected := math.Inf(1)
+				for j, site := range sites {
+					if site {
+						expected = min(expected, Distance(pixelCenter(i), pixelCenter(j)))
+					}
+				}
+				actual := float64(distances[i])
+				if math.IsInf(expected, 1) {
+					if !math.IsInf(actual, 1) {
+						t.Fatalf("expected no site for pixel %d, got %f", i, actual)
+					}
+					continue
+				}
+				// float32 distances across the globe are only accurate to a couple of metres, and propagation never
+				// settles on a nearer site than the nearest but can on one up to 0.11 pixel spacings farther
+				if actual < expected-2 || actual > expected+2+0.11*spacing {
+					t.Fatalf("expected %f m from pixel %d to the nearest site, got %f", expected, i, actual)
+				}
+				if actual > expected+2 {
+					off++
+				}
+			}
+			// fewer than 1 in 1000 pixels settle on a farther site
+			if off*1000 >= len(sites) {
+				t.Errorf("expected fewer than 1 in 1000 of %d pixels to settle on a farther site, got %d", len(sites), off)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package gebco
+
+import (
+	"cmp"
+	"container/heap"
+	"fmt"
+	"slices"
+)
+
+// D8 flow direction codes, following the ESRI convention, give the neighbour a pixel drains to. Pits, which drain
+// nowhere, have code 0.
+const (
+	FlowEast      uint8 = 1
+	FlowSouthEast uint8 = 2
+	FlowSouth     uint8 = 4
+	FlowSouthWest uint8 = 8
+	FlowWest      uint8 = 16
+	FlowNorthWest uint8 = 32
+	FlowNorth     uint8 = 64
+	FlowNorthEast uint8 = 128
+)
+
+// flowCodes holds the D8 code of each step to a neighbour, indexed by (dy+1)*3 + dx+1.
+var flowCodes = [9]uint8{
+	FlowNorthWest, FlowNorth, FlowNorthEast,
+	FlowWest, 0, FlowEast,
+	FlowSouthWest, FlowSouth, FlowSouthEast,
+}
+
+// DrainageOptions configures DrainageBasins.
+type DrainageOptions struct {
+	Region    Bounds  // The region to drain, expanded to whole pixels of the grid.
+	OceanOnly bool    // Whether to drain only the seafloor, leaving land out of the domain.
+	MinDepth  float64 // The depth in metres of a basin below its spill point below which it is merged into a neighbour.
+	MinArea   float64 // The area in square metres below the spill point below which a basin is merged into a neighbour.
+}
+
+// Basin is the catchment of a pit in the sub-ice surface, the enclosed depression that all of its pixels drain to.
+// Its polygon outlines its pixels, and its area is the total area of those pixels.
+type Basin struct {
+	Polygon
+	Id       int     `json:"id"` // The value of the basin in the basin band of the drainage raster.
+	Pixels   int     `json:"pixels"`
+	Pit      LatLng  `json:"pit"`      // The centre of the deepest pixel of the basin.
+	PitDepth float64 `json:"pitDepth"` // The depth in metres of the pit, negative above sea level.
+	// SpillDepth is the depth in metres of the lowest point over which the basin overflows, or of the shallowest
+	// pixel of its piece of the domain for the deepest basin, which never overflows.
+	SpillDepth float64 `json:"spillDepth"`
+	Relief     float64 `json:"relief"` // The depth in metres of the pit below the spill point.
+}
+
+// Feature returns the basin as a GeoJSON Polygon feature, cut at the antimeridian if it crosses it, with its area
+// in square kilometres, pit and spill depths as properties.
+func (b Basin) Feature() GeoJsonFeature {
+	return NewGeoJsonFeature(b.Geometry(), map[string]any{
+		"id":         b.Id,
+		"areaKm2":    b.Area / 1e6,
+		"pixels":     b.Pixels,
+		"pitLat":     b.Pit.Lat,
+		"pitLng":     b.Pit.Lng,
+		"pitDepth":   b.PitDepth,
+		"spillDepth": b.SpillDepth,
+		"relief":     b.Relief,
+	})
+}
+
+// Drainage is the flow of a region over its sub-ice surface into its basins.
+type Drainage struct {
+	// Raster is the region in plate carrée with a "flow-direction" band of D8 codes, an "accumulation" band of the
+	// area in square metres draining through each pixel including its own, and a "basin" band of the id of the basin
+	// each pixel drains to. Pixels outside the domain are NaN.
+	Raster *Raster `json:"-"`
+	Basins []Basin `json:"basins"` // Largest first, with ids counting from 1.
+}
+
+// DrainageBasins drains the sub-ice surface of a region into basins. The pits of the surface are found by flooding
+// it from the bottom up, merging each pit shallower than the minimum depth or area below its spill point into the
+// basin it overflows into, so that noise and small hollows do not fragment the basins. The surface is then flooded
+// upwards from the remaining pits with a priority flood, which gives every pixel a D8 flow direction towards the pit
+// it drains to, across depressions and flats as the overflowing water would, and a basin. Pixels drain across edges
+// and corners and across the antimeridian in a region spanning the globe; the edges of a region that does not span
+// the globe, and land when draining only the seafloor, are walls, so basins cut by them end there.
+func DrainageBasins(grid *Grid, opts DrainageOptions) (*Drainage, error) {
+	if opts.MinDepth < 0 {
+		return nil, fmt.Errorf("invalid minimum basin depth %g", opts.MinDepth)
+	}
+	region, err := grid.Region(opts.Region)
+	if err != nil {
+		return nil, err
+	}
+	width, height := region.Width, region.Height
+	samples, err := region.ReadSamples()
+	if err != nil {
+		return nil, err
+	}
+	domain := make([]bool, len(samples))
+	depth := make([]float32, len(samples))
+	for pixel, sample := range samples {
+		domain[pixel] = !opts.OceanOnly || !sample.IsLand()
+		depth[pixel] = -float32(sample.SubIce)
+	}
+
+	// the pits are the peaks of the depth, their spill points the cols between them, and the deepest pit of each
+	// piece of the domain is always kept so that every pixel drains somewhere
+	pits := []prominence{}
+	for _, p := range prominences(region, depth, domain) {
+		if p.highest || (float64(depth[p.summit]-p.base) >= opts.MinDepth && p.area >= opts.MinArea) {
+			pits = append(pits, p)
+		}
+	}
+
+	// flood upwards from the pits, raising the pixels of depressions to the level of the flood and crossing flats in
+	// the order they were reached, so that every pixel drains towards the pixel it was reached from
+	const unreached = -1
+	basin := make([]int32, len(samples))
+	for pixel := range basin {
+		basin[pixel] = unreached
+	}
+	flow := make([]uint8, len(samples))
+	downstream := make([]int32, len(samples))
+	queue := &floodQueue{}
+	pushed := 0
+	push := func(pixel int, level float32) {
+		heap.Push(queue, floodNode{pixel: pixel, level: level, order: pushed})
+		pushed++
+	}
+	reached := make([]int32, 0, len(samples))
+	for i, pit := range pits {
+		basin[pit.summit], downstream[pit.summit] = int32(i), unreached
+		push(pit.summit, -depth[pit.summit])
+	}
+	for queue.Len() > 0 {
+		node := heap.Pop(queue).(floodNode)
+		reached = append(reached, int32(node.pixel))
+		i, j := node.pixel%width, node.pixel/width
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				ni, nj := i+dx, j+dy
+				if nj < 0 || nj >= height || (dx == 0 && dy == 0) {
+					continue
+				}
+				if ni < 0 || ni >= width {
+					if !region.Wraps() {
+						continue
+					}
+					ni = (ni + width) % width
+				}
+				neighbour := region.Index(ni, nj)
+				if !domain[neighbour] || basin[neighbour] != unreached {
+					continue
+				}
+				basin[neighbour], downstream[neighbour] = basin[node.pixel], int32(node.pixel)
+				flow[neighbour] = flowCodes[(1-dy)*3+1-dx]
+				push(neighbour, max(-depth[neighbour], node.level))
+			}
+		}
+	}
+
+	// every pixel is reached after the pixel it drains to, so accumulate in reverse
+	accumulation := make([]float64, len(samples))
+	for _, pixel := range slices.Backward(reached) {
+		accumulation[pixel] += CellArea(grid.PixelCenter(0, region.Y+int(pixel)/width).Lat, grid.DegreesPerPixel())
+		if down := downstream[pixel]; down != unreached {
+			accumulation[down] += accumulation[pixel]
+		}
+	}
+
+	drainage := &Drainage{Basins: make([]Basin, len(pits))}
+	for i, pit := range pits {
+		drainage.Basins[i] = Basin{
+			Pit:        grid.PixelCenter(grid.wrapX(region.X+pit.summit%width), region.Y+pit.summit/width),
+			PitDepth:   float64(depth[pit.summit]),
+			SpillDepth: float64(pit.base),
+			Relief:     float64(depth[pit.summit] - pit.base),
+		}
+	}
+	for pixel, b := range basin {
+		if b != unreached {
+			drainage.Basins[b].Pixels++
+			drainage.Basins[b].Area += CellArea(grid.PixelCenter(0, region.Y+pixel/width).Lat, grid.DegreesPerPixel())
+		}
+	}
+
+	// number the basins from the largest and outline them within their bounding boxes, which span the whole region
+	// for a basin joining across the antimeridian
+	ranks := make([]int, len(pits))
+	for i := range ranks {
+		ranks[i] = i
+	}
+	slices.SortStableFunc(ranks, func(a, b int) int {
+		return cmp.Compare(drainage.Basins[b].Area, drainage.Basins[a].Area)
+	})
+	ids := make([]int, len(pits))
+	for rank, i := range ranks {
+		ids[i] = rank + 1
+	}
+	minI, maxI, minJ, maxJ := make([]int, len(pits)), make([]int, len(pits)), make([]int, len(pits)), make([]int, len(pits))
+	for i := range pits {
+		minI[i], maxI[i], minJ[i], maxJ[i] = width, -1, height, -1
+	}
+	for pixel, b := range basin {
+		if b == unreached {
+			continue
+		}
+		i, j := pixel%width, pixel/width
+		minI[b], maxI[b], minJ[b], maxJ[b] = min(minI[b], i), max(maxI[b], i), min(minJ[b], j), max(maxJ[b], j)
+	}
+	for b := range drainage.Basins {
+		outline := GridRegion{Grid: grid, X: region.X + minI[b], Y: region.Y + minJ[b], Width: maxI[b] - minI[b] + 1, Height: maxJ[b] - minJ[b] + 1}
+		inside := make([]bool, outline.Width*outline.Height)
+		for j := range outline.Height {
+			for i := range outline.Width {
+				inside[outline.Index(i, j)] = basin[region.Index(minI[b]+i, minJ[b]+j)] == int32(b)
+			}
+		}
+		if polygons := polygonise(outline, inside, nil); len(polygons) > 0 {
+			// the largest exterior, as the flood joins pixels at corners that the outline may not
+			area := drainage.Basins[b].Area
+			drainage.Basins[b].Polygon = polygons[len(polygons)-1]
+			drainage.Basins[b].Area = area
+		}
+		drainage.Basins[b].Id = ids[b]
+	}
+
+	step := grid.DegreesPerPixel()
+	drainage.Raster = NewRaster(PlateCarree{}, region.West(), region.North(), step, step, width, height, "flow-direction", "accumulation", "basin")
+	for pixel, b := range basin {
+		if b != unreached {
+			drainage.Raster.Bands[0][pixel] = float32(flow[pixel])
+			drainage.Raster.Bands[1][pixel] = float32(accumulation[pixel])
+			drainage.Raster.Bands[2][pixel] = float32(ids[b])
+		}
+	}
+	slices.SortStableFunc(drainage.Basins, func(a, b Basin) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+	return drainage, nil
+}
+
+// floodNode is a pixel waiting to be flooded, ordered by the level of the flood reaching it and then by when it was
+// reached.
+type floodNode struct {
+	pixel int
+	level float32
+	order int
+}
+
+type floodQueue []floodNode
+
+func (q floodQueue) Len() int { return len(q) }
+func (q floodQueue) Less(i, j int) bool {
+	return q[i].level < q[j].level || (q[i].level == q[j].level && q[i].order < q[j].order)
+}
+func (q floodQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *floodQueue) Push(x any)   { *q = append(*q, x.(floodNode)) }
+func (q *floodQueue) Pop() any {
+	old := *q
+	node := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return node
+}
+
+// FlowOffset returns the step in pixels to the neighbour a D8 flow direction code drains to, with y increasing
+// southwards, or false for a pit or an invalid code.
+func FlowOffset(code uint8) (dx, dy int, ok bool) {
+	if code == 0 {
+		return 0, 0, false
+	}
+	index := slices.Index(flowCodes[:], code)
+	if index < 0 {
+		return 0, 0, false
+	}
+	return index%3 - 1, index/3 - 1, true
+}
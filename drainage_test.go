@@ -0,0 +1,103 @@
+package gebco
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDrainageBasins(t *testing.T) {
+	// a flat seafloor of 10 degree pixels with a deep 3x3 pit, a smaller pit, a shallow hollow and an island
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		switch {
+		case x == 6 && y == 9:
+			return GebcoSample{Ice: -4000, SubIce: -4000, Tid: GebcoTypeMultiBeam}
+		case x >= 5 && x < 8 && y >= 8 && y < 11:
+			return GebcoSample{Ice: -3000, SubIce: -3000, Tid: GebcoTypeMultiBeam}
+		case x == 20 && y == 5:
+			return GebcoSample{Ice: -2000, SubIce: -2000, Tid: GebcoTypeMultiBeam}
+		case x == 30 && y == 12:
+			return GebcoSample{Ice: -1010, SubIce: -1010, Tid: GebcoTypeMultiBeam}
+		case x >= 14 && x < 16 && y == 4:
+			return GebcoSample{Ice: 100, SubIce: 100, Tid: GebcoTypeLand}
+		}
+		return GebcoSample{Ice: -1000, SubIce: -1000, Tid: GebcoTypeMultiBeam}
+	})
+	grid := dataset.Grid()
+
+	drainage, err := DrainageBasins(grid, DrainageOptions{Region: GlobalBounds, OceanOnly: true, MinDepth: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drainage.Basins) != 2 {
+		t.Fatalf("expected the hollow merged into one of 2 basins, got %+v", drainage.Basins)
+	}
+	deep, small := drainage.Basins[0], drainage.Basins[1]
+	if deep.PitDepth == 2000 {
+		deep, small = small, deep
+	}
+	if deep.PitDepth != 4000 || deep.SpillDepth != 1000 || deep.Relief != 3000 || deep.Pit != (LatLng{Lat: -5, Lng: -115}) {
+		t.Errorf("unexpected deep basin %+v", deep)
+	}
+	if small.PitDepth != 2000 || small.SpillDepth != 1000 || small.Relief != 1000 {
+		t.Errorf("unexpected small basin %+v", small)
+	}
+	if deep.Pixels+small.Pixels != 36*18-2 {
+		t.Errorf("expected every ocean pixel in a basin, got %d and %d", deep.Pixels, small.Pixels)
+	}
+	if !ringContains(deep.Exterior, deep.Pit) || !ringContains(small.Exterior, small.Pit) {
+		t.Errorf("expected the basin polygons around their pits")
+	}
+
+	band := func(band, x, y int) float32 {
+		return drainage.Raster.Bands[band][y*36+x]
+	}
+	if band(0, 5, 8) != float32(FlowSouthEast) || band(0, 6, 9) != 0 || band(0, 7, 10) != float32(FlowNorthWest) {
+		t.Errorf("expected the rim of the deep pit to drain into it, got %v %v %v", band(0, 5, 8), band(0, 6, 9), band(0, 7, 10))
+	}
+	if band(0, 30, 12) == 0 || !math.IsNaN(float64(band(0, 14, 4))) {
+		t.Errorf("expected the hollow to drain and the island outside the domain, got %v and %v", band(0, 30, 12), band(0, 14, 4))
+	}
+	if pit := float64(band(1, 6, 9)); math.Abs(pit-deep.Area)/deep.Area > 1e-6 {
+		t.Errorf("expected the whole deep basin of %g square metres to accumulate at its pit, got %g", deep.Area, pit)
+	}
+	if band(2, 6, 9) != float32(deep.Id) || band(2, 20, 5) != float32(small.Id) {
+		t.Errorf("expected the pits labelled with their basins")
+	}
+
+	// following the flow from any pixel ends in the pit of its basin
+	x, y := 33, 1
+	for steps := 0; band(0, x, y) != 0 && steps < 100; steps++ {
+		dx, dy, ok := FlowOffset(uint8(band(0, x, y)))
+		if !ok {
+			t.Fatalf("invalid flow direction %v at (%d,%d)", band(0, x, y), x, y)
+		}
+		x, y = (x+dx+36)%36, y+dy
+	}
+	if id := band(2, 33, 1); (id == float32(deep.Id) && (x != 6 || y != 9)) || (id == float32(small.Id) && (x != 20 || y != 5)) {
+		t.Errorf("expected the flow to end in the pit of basin %v, got (%d,%d)", id, x, y)
+	}
+
+	// without the ocean mask the island is the spill point of the deepest basin
+	drainage, err = DrainageBasins(grid, DrainageOptions{Region: GlobalBounds, MinDepth: 5000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drainage.Basins) != 1 || drainage.Basins[0].SpillDepth != -100 || drainage.Basins[0].Pixels != 36*18 {
+		t.Errorf("expected a single basin spilling over the island, got %+v", drainage.Basins)
+	}
+}
+
+func TestFlowOffset(t *testing.T) {
+	for _, code := range []uint8{FlowEast, FlowSouthEast, FlowSouth, FlowSouthWest, FlowWest, FlowNorthWest, FlowNorth, FlowNorthEast} {
+		dx, dy, ok := FlowOffset(code)
+		if !ok || flowCodes[(dy+1)*3+dx+1] != code {
+			t.Errorf("unexpected offset (%d,%d) for code %d", dx, dy, code)
+		}
+	}
+	if _, _, ok := FlowOffset(0); ok {
+		t.Errorf("expected no offset for a pit")
+	}
+	if _, _, ok := FlowOffset(3); ok {
+		t.Errorf("expected no offset for an invalid code")
+	}
+}
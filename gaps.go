@@ -0,0 +1,196 @@
+package gebco
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// SurveyGapOrder selects the order in which SurveyGaps returns gaps.
+type SurveyGapOrder uint8
+
+const (
+	SurveyGapsByArea     SurveyGapOrder = iota // Largest area first.
+	SurveyGapsByDepth                          // Deepest seafloor first.
+	SurveyGapsByLatitude                       // Northernmost centroid first.
+)
+
+func (o SurveyGapOrder) MarshalText() ([]byte, error) {
+	switch o {
+	case SurveyGapsByArea:
+		return []byte("area"), nil
+	case SurveyGapsByDepth:
+		return []byte("depth"), nil
+	case SurveyGapsByLatitude:
+		return []byte("latitude"), nil
+	default:
+		return nil, fmt.Errorf("unknown SurveyGapOrder %d", o)
+	}
+}
+
+// UnmarshalText parses a survey gap order by name (area, depth or latitude).
+func (o *SurveyGapOrder) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "area":
+		*o = SurveyGapsByArea
+	case "depth":
+		*o = SurveyGapsByDepth
+	case "latitude":
+		*o = SurveyGapsByLatitude
+	default:
+		return fmt.Errorf("unknown survey gap order %q (expected area, depth or latitude)", text)
+	}
+	return nil
+}
+
+// SurveyGapOptions configures SurveyGaps.
+type SurveyGapOptions struct {
+	Region  Bounds         // The region to search, expanded to whole pixels of the grid.
+	MinArea float64        // The area in square metres below which gaps are dropped.
+	Order   SurveyGapOrder // The order in which to return the gaps.
+}
+
+// SurveyGap is a contiguous area of seafloor whose depths are all derived indirectly or taken from unknown
+// sources, so that it has never been measured directly. Its polygon outlines its pixels, and its area is the total
+// area of those pixels.
+type SurveyGap struct {
+	Polygon
+	Pixels   int     `json:"pixels"`
+	MinDepth float64 `json:"minDepth"` // The depth in metres of the shallowest seafloor in the gap.
+	MaxDepth float64 `json:"maxDepth"` // The depth in metres of the deepest seafloor in the gap.
+	Centroid LatLng  `json:"centroid"`
+}
+
+// Feature returns the gap as a GeoJSON Polygon feature, cut at the antimeridian if it crosses it, with its area
+// in square kilometres, depth range and centroid as properties.
+func (g SurveyGap) Feature() GeoJsonFeature {
+	return NewGeoJsonFeature(g.Geometry(), map[string]any{
+		"areaKm2":     g.Area / 1e6,
+		"pixels":      g.Pixels,
+		"minDepth":    g.MinDepth,
+		"maxDepth":    g.MaxDepth,
+		"centroidLat": g.Centroid.Lat,
+		"centroidLng": g.Centroid.Lng,
+	})
+}
+
+// IsSurveyGap reports whether a sample is seafloor whose depth was not measured directly.
+func (s GebcoSample) IsSurveyGap() bool {
+	return !s.IsLand() && (s.Tid.IsIndirect() || s.Tid.IsUnknown())
+}
+
+// SurveyGaps finds the connected components of survey gap pixels in a region, joining pixels that share an edge
+// or a corner and continuing across the antimeridian in a region spanning the globe, and outlines those at least
+// the minimum area as polygons. The grid must have type IDs.
+func SurveyGaps(grid *Grid, opts SurveyGapOptions) ([]SurveyGap, error) {
+	if !grid.HasTid() {
+		return nil, fmt.Errorf("layer %s has no type ID channel to find survey gaps in", grid.Name())
+	}
+	region, err := grid.Region(opts.Region)
+	if err != nil {
+		return nil, err
+	}
+	width, height := region.Width, region.Height
+	gap := make([]bool, width*height)
+	depth := make([]int16, width*height)
+	buf := grid.newSampleBuffer()
+	for j := range height {
+		for i := range width {
+			sample, err := grid.sampleInto(region.X+i, region.Y+j, buf)
+			if err != nil {
+				return nil, err
+			}
+			gap[region.Index(i, j)], depth[region.Index(i, j)] = sample.IsSurveyGap(), -sample.SubIce
+		}
+	}
+
+	labels := make([]int32, width*height)
+	gaps := []SurveyGap{}
+	stack := []int{}
+	label := int32(0)
+	for start := range gap {
+		if !gap[start] || labels[start] != 0 {
+			continue
+		}
+		label++
+		labels[start] = label
+		stack = append(stack[:0], start)
+
+		component := SurveyGap{MinDepth: math.Inf(1), MaxDepth: math.Inf(-1)}
+		minI, maxI, minJ, maxJ := width, -1, height, -1
+		seam := false // whether the component joins across the antimeridian of a region that wraps
+		var cx, cy, cz float64
+		for len(stack) > 0 {
+			pixel := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			i, j := pixel%width, pixel/width
+
+			center := grid.PixelCenter(grid.wrapX(region.X+i), region.Y+j)
+			area := CellArea(center.Lat, grid.DegreesPerPixel())
+			lat, lng := radians(center.Lat), radians(center.Lng)
+			cx += area * math.Cos(lat) * math.Cos(lng)
+			cy += area * math.Cos(lat) * math.Sin(lng)
+			cz += area * math.Sin(lat)
+			component.Area += area
+			component.Pixels++
+			component.MinDepth = min(component.MinDepth, float64(depth[pixel]))
+			component.MaxDepth = max(component.MaxDepth, float64(depth[pixel]))
+			minI, maxI, minJ, maxJ = min(minI, i), max(maxI, i), min(minJ, j), max(maxJ, j)
+
+			for nj := j - 1; nj <= j+1; nj++ {
+				for ni := i - 1; ni <= i+1; ni++ {
+					if nj < 0 || nj >= height {
+						continue
+					}
+					wrapped := ni
+					if ni < 0 || ni >= width {
+						if !region.Wraps() {
+							continue
+						}
+						wrapped, seam = (ni+width)%width, true
+					}
+					if neighbour := region.Index(wrapped, nj); gap[neighbour] && labels[neighbour] == 0 {
+						labels[neighbour] = label
+						stack = append(stack, neighbour)
+					}
+				}
+			}
+		}
+		component.Centroid = LatLng{Lat: degrees(math.Atan2(cz, math.Hypot(cx, cy))), Lng: degrees(math.Atan2(cy, cx))}
+		if component.Area < opts.MinArea {
+			continue
+		}
+
+		// outline the component within its bounding rows, and columns unless it joins across the antimeridian
+		outline := GridRegion{Grid: grid, X: region.X + minI, Y: region.Y + minJ, Width: maxI - minI + 1, Height: maxJ - minJ + 1}
+		if seam {
+			outline.X, outline.Width, minI = region.X, width, 0
+		}
+		inside := make([]bool, outline.Width*outline.Height)
+		for j := range outline.Height {
+			for i := range outline.Width {
+				inside[outline.Index(i, j)] = labels[region.Index(minI+i, minJ+j)] == label
+			}
+		}
+		if polygons := polygonise(outline, inside, nil); len(polygons) > 0 {
+			// the only exterior, as corners join pixels both here and in the outline
+			area := component.Area
+			component.Polygon = polygons[len(polygons)-1]
+			component.Area = area
+		}
+		gaps = append(gaps, component)
+	}
+
+	slices.SortStableFunc(gaps, func(a, b SurveyGap) int {
+		switch opts.Order {
+		case SurveyGapsByDepth:
+			return cmp.Compare(b.MaxDepth, a.MaxDepth)
+		case SurveyGapsByLatitude:
+			return cmp.Compare(b.Centroid.Lat, a.Centroid.Lat)
+		default:
+			return cmp.Compare(b.Area, a.Area)
+		}
+	})
+	return gaps, nil
+}
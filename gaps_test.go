@@ -0,0 +1,70 @@
+package gebco
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSurveyGaps(t *testing.T) {
+	// a 3x2 pixel interpolated gap, two unknown pixels joined at a corner, a gravity-derived gap across the
+	// antimeridian and an interpolated pixel on land, all within multibeam survey
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		switch {
+		case x >= 10 && x < 13 && (y == 10 || y == 11):
+			return GebcoSample{Ice: int16(-4000 - 100*x), SubIce: int16(-4000 - 100*x), Tid: GebcoTypeInterpolated}
+		case (x == 20 && y == 3) || (x == 21 && y == 4):
+			return GebcoSample{Ice: -200, SubIce: -200, Tid: GebcoTypeUnknown}
+		case (x == 35 || x == 0) && y == 8:
+			return GebcoSample{Ice: -6000, SubIce: -6000, Tid: GebcoTypeSatelliteGravity}
+		case x == 30 && y == 12:
+			return GebcoSample{Ice: 300, SubIce: 300, Tid: GebcoTypeInterpolated}
+		}
+		return GebcoSample{Ice: -1000, SubIce: -1000, Tid: GebcoTypeMultiBeam}
+	})
+	grid := dataset.Grid()
+
+	gaps, err := SurveyGaps(grid, SurveyGapOptions{Region: GlobalBounds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gaps) != 3 {
+		t.Fatalf("expected 3 gaps, got %d", len(gaps))
+	}
+	block, antimeridian, corner := gaps[0], gaps[1], gaps[2]
+	if block.Pixels != 6 || block.MinDepth != 5000 || block.MaxDepth != 5200 {
+		t.Errorf("unexpected block gap %+v", block)
+	}
+	if math.Abs(block.Centroid.Lat+20) > 0.1 || math.Abs(block.Centroid.Lng+65) > 1e-6 {
+		t.Errorf("expected block centroid at -20,-65, got %v", block.Centroid)
+	}
+	if !ringContains(block.Exterior, block.Centroid) || block.Geometry().Type != "Polygon" {
+		t.Errorf("expected a polygon around the block centroid, got %v", block.Exterior)
+	}
+	if antimeridian.Pixels != 2 || math.Abs(antimeridian.Centroid.Lat-5) > 0.1 || math.Abs(math.Abs(antimeridian.Centroid.Lng)-180) > 1e-6 {
+		t.Errorf("expected one gap across the antimeridian, got %+v", antimeridian)
+	}
+	if antimeridian.Geometry().Type != "MultiPolygon" {
+		t.Errorf("expected the antimeridian gap to be cut, got %s", antimeridian.Geometry().Type)
+	}
+	if corner.Pixels != 2 || len(corner.Exterior) == 0 {
+		t.Errorf("expected the corner gap to join its pixels, got %+v", corner)
+	}
+
+	// filtered by size and ordered by latitude
+	gaps, err = SurveyGaps(grid, SurveyGapOptions{Region: GlobalBounds, MinArea: corner.Area + 1, Order: SurveyGapsByLatitude})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gaps) != 2 || gaps[0].Pixels != 2 || gaps[1].Pixels != 6 {
+		t.Errorf("expected the antimeridian gap then the block, got %d gaps", len(gaps))
+	}
+
+	// filtered by region, which no longer joins the gap across the antimeridian
+	gaps, err = SurveyGaps(grid, SurveyGapOptions{Region: Bounds{West: -180, South: -60, East: 0, North: 20}, Order: SurveyGapsByDepth})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gaps) != 2 || gaps[0].MaxDepth != 6000 || gaps[0].Pixels != 1 || gaps[1].Pixels != 6 {
+		t.Errorf("expected the western half of the antimeridian gap then the block, got %d gaps", len(gaps))
+	}
+}
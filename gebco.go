@@ -51,6 +51,35 @@ func (g GebcoDataType) fileString() string {
 	}
 }
 
+// MarshalText returns the name of the Pixi channel holding this data type.
+func (g GebcoDataType) MarshalText() ([]byte, error) {
+	switch g {
+	case GebcoDataIce:
+		return []byte("ice"), nil
+	case GebcoDataSubIce:
+		return []byte("sub-ice"), nil
+	case GebcoDataTypeId:
+		return []byte("tid"), nil
+	default:
+		return nil, fmt.Errorf("unknown GebcoDataType %d", g)
+	}
+}
+
+// UnmarshalText parses a data type from the name of its Pixi channel (ice, sub-ice or tid).
+func (g *GebcoDataType) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "ice":
+		*g = GebcoDataIce
+	case "sub-ice":
+		*g = GebcoDataSubIce
+	case "tid":
+		*g = GebcoDataTypeId
+	default:
+		return fmt.Errorf("unknown GEBCO data type %q (expected ice, sub-ice or tid)", text)
+	}
+	return nil
+}
+
 // GebcoTypeId represents the source type of a GEBCO depth value.
 type GebcoTypeId byte
 
@@ -86,6 +115,24 @@ const (
 	GebcoTypeSteering     GebcoTypeId = 72 // Depth value used to constrain the grid in areas of poor data coverage.
 )
 
+// IsDirect reports whether the type ID marks a depth measured directly, such as by an echo sounder, rather than
+// derived indirectly or taken from an unknown source.
+func (t GebcoTypeId) IsDirect() bool {
+	return t >= GebcoTypeSingleBeam && t <= GebcoTypeCombination
+}
+
+// IsIndirect reports whether the type ID marks a depth derived indirectly, such as by interpolation or from
+// satellite gravity.
+func (t GebcoTypeId) IsIndirect() bool {
+	return t >= GebcoTypeSatelliteGravity && t <= GebcoTypeArgoDrift
+}
+
+// IsUnknown reports whether the type ID marks a depth from a pregenerated grid, an unknown source or a steering
+// point.
+func (t GebcoTypeId) IsUnknown() bool {
+	return t >= GebcoTypePregenerated && t <= GebcoTypeSteering
+}
+
 type GebcoTifFile struct {
 	x, y int
 	year int
@@ -0,0 +1,265 @@
+package gebco
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	EarthRadius float64 = 6371008.8 // The mean radius of the Earth in metres (IUGG), used for spherical geodesy.
+)
+
+// LatLng is a geographic position in decimal degrees.
+type LatLng struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// ParseLatLng parses a position written as "lat,lng" in decimal degrees.
+func ParseLatLng(s string) (LatLng, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return LatLng{}, fmt.Errorf("invalid position %q: expected lat,lng", s)
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return LatLng{}, fmt.Errorf("invalid latitude in %q: %w", s, err)
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return LatLng{}, fmt.Errorf("invalid longitude in %q: %w", s, err)
+	}
+	if lat < -90 || lat > 90 {
+		return LatLng{}, fmt.Errorf("invalid latitude in %q: must be within [-90, 90]", s)
+	}
+	return LatLng{Lat: lat, Lng: lng}, nil
+}
+
+// ParseLatLngs parses a list of positions separated by semicolons, e.g. "10,20;11,21".
+func ParseLatLngs(s string) ([]LatLng, error) {
+	points := []LatLng{}
+	for part := range strings.SplitSeq(s, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		point, err := ParseLatLng(part)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+func (p LatLng) String() string {
+	return fmt.Sprintf("(%.6f,%.6f)", p.Lat, p.Lng)
+}
+
+// NormalizeLng wraps a longitude into the range [-180, 180).
+func NormalizeLng(lng float64) float64 {
+	lng = math.Mod(lng+180, 360)
+	if lng < 0 {
+		lng += 360
+	}
+	return lng - 180
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+// Distance returns the great-circle distance in metres between two positions on a spherical Earth.
+func Distance(a, b LatLng) float64 {
+	return EarthRadius * angularDistance(a, b)
+}
+
+// angularDistance returns the central angle in radians between two positions using the haversine formula.
+func angularDistance(a, b LatLng) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLng := radians(b.Lng - a.Lng)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	h = min(1, h) // rounding can push antipodal points past 1
+	return 2 * math.Atan2(math.Sqrt(h), math.Sqrt(1-h))
+}
+
+// antipodal reports whether two positions lie so nearly opposite each other, within a few metres, that no single
+// great circle joins them.
+func antipodal(a, b LatLng) bool {
+	return math.Pi-angularDistance(a, b) < 1e-6
+}
+
+// InitialBearing returns the initial bearing in degrees clockwise from north when travelling from a to b
+// along a great circle, in the range [0, 360).
+func InitialBearing(a, b LatLng) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLng := radians(b.Lng - a.Lng)
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// DistanceToArc returns the distance in metres from a position to the nearest point of the great-circle arc
+// between a and b, which is undefined when they are antipodal.
+func DistanceToArc(p, a, b LatLng) float64 {
+	toP := angularDistance(a, p)
+	angle := radians(InitialBearing(a, p) - InitialBearing(a, b))
+	crossTrack := math.Asin(math.Sin(toP) * math.Sin(angle))
+	if math.Cos(angle) < 0 {
+		// p lies behind a
+		return EarthRadius * toP
+	}
+	alongTrack := math.Acos(max(-1, min(1, math.Cos(toP)/math.Cos(crossTrack))))
+	if alongTrack > angularDistance(a, b) {
+		return Distance(p, b)
+	}
+	return EarthRadius * math.Abs(crossTrack)
+}
+
+// Intermediate returns the position a fraction of the way along the great circle from a to b, which is undefined
+// when they are antipodal.
+func Intermediate(a, b LatLng, fraction float64) LatLng {
+	delta := angularDistance(a, b)
+	if delta == 0 {
+		return a
+	}
+	lat1, lng1 := radians(a.Lat), radians(a.Lng)
+	lat2, lng2 := radians(b.Lat), radians(b.Lng)
+	wa := math.Sin((1-fraction)*delta) / math.Sin(delta)
+	wb := math.Sin(fraction*delta) / math.Sin(delta)
+	x := wa*math.Cos(lat1)*math.Cos(lng1) + wb*math.Cos(lat2)*math.Cos(lng2)
+	y := wa*math.Cos(lat1)*math.Sin(lng1) + wb*math.Cos(lat2)*math.Sin(lng2)
+	z := wa*math.Sin(lat1) + wb*math.Sin(lat2)
+	return LatLng{
+		Lat: degrees(math.Atan2(z, math.Sqrt(x*x+y*y))),
+		Lng: NormalizeLng(degrees(math.Atan2(y, x))),
+	}
+}
+
+// Destination returns the position reached by travelling distance metres from p along a great circle
+// starting at the given bearing in degrees clockwise from north.
+func Destination(p LatLng, bearing float64, distance float64) LatLng {
+	delta := distance / EarthRadius
+	theta := radians(bearing)
+	lat1, lng1 := radians(p.Lat), radians(p.Lng)
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta))
+	lng2 := lng1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*math.Sin(lat2))
+	return LatLng{Lat: degrees(lat2), Lng: NormalizeLng(degrees(lng2))}
+}
+
+// CellSize returns the east-west and north-south distances in metres spanned by a grid cell of the given angular
+// size centred on a latitude. The east-west distance shrinks with the cosine of latitude.
+func CellSize(lat float64, degreesPerPixel float64) (dx, dy float64) {
+	dy = EarthRadius * radians(degreesPerPixel)
+	dx = dy * math.Cos(radians(lat))
+	return dx, dy
+}
+
+// CellArea returns the area in square metres of a grid cell of the given angular size centred on a latitude, the
+// exact area of the zone of the sphere between its parallels rather than the product of CellSize, so that the cells
+// of a row of the globe sum to its area even at the poles. Parallels beyond the poles are clamped to them.
+func CellArea(lat float64, degreesPerPixel float64) float64 {
+	north := radians(min(lat+degreesPerPixel/2, 90))
+	south := radians(max(lat-degreesPerPixel/2, -90))
+	return EarthRadius * EarthRadius * radians(degreesPerPixel) * (math.Sin(north) - math.Sin(south))
+}
+
+// RingArea returns the area in square metres enclosed by a ring of positions whose last position repeats its
+// first, positive when the ring runs anticlockwise on a north-up map. Longitudes may be unwrapped. Edges follow
+// parallels and meridians closely enough for rings traced on the grid.
+func RingArea(ring []LatLng) float64 {
+	sum := 0.0
+	for i := 1; i < len(ring); i++ {
+		a, b := ring[i-1], ring[i]
+		sum += radians(b.Lng-a.Lng) * (2 + math.Sin(radians(a.Lat)) + math.Sin(radians(b.Lat)))
+	}
+	return -sum * EarthRadius * EarthRadius / 2
+}
+
+// Bounds is a geographic rectangle in decimal degrees. A region crossing the antimeridian has an East edge less
+// than its West edge.
+type Bounds struct {
+	West  float64 `json:"west"`
+	South float64 `json:"south"`
+	East  float64 `json:"east"`
+	North float64 `json:"north"`
+}
+
+// GlobalBounds covers the whole GEBCO grid.
+var GlobalBounds = Bounds{West: -180, South: -90, East: 180, North: 90}
+
+// ParseBounds parses a rectangle written as "west,south,east,north" in decimal degrees, crossing the antimeridian
+// when east is less than west. The globe is spanned only by an explicit west of -180 and east of 180.
+func ParseBounds(s string) (Bounds, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return Bounds{}, fmt.Errorf("invalid bounds %q: expected west,south,east,north", s)
+	}
+	values := make([]float64, 4)
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return Bounds{}, fmt.Errorf("invalid bounds %q: %w", s, err)
+		}
+		values[i] = value
+	}
+	bounds := Bounds{West: values[0], South: values[1], East: values[2], North: values[3]}
+	if !(bounds.South < bounds.North && bounds.South >= -90 && bounds.North <= 90) {
+		return Bounds{}, fmt.Errorf("invalid bounds %q: latitudes must satisfy -90 <= south < north <= 90", s)
+	}
+	if !(bounds.West >= -180 && bounds.West <= 180 && bounds.East >= -180 && bounds.East <= 180) {
+		return Bounds{}, fmt.Errorf("invalid bounds %q: longitudes must lie in [-180, 180]", s)
+	}
+	if NormalizeLng(bounds.West) == NormalizeLng(bounds.East) && !(bounds.West == -180 && bounds.East == 180) {
+		return Bounds{}, fmt.Errorf("invalid bounds %q: west and east must differ, or be -180 and 180 to span the globe", s)
+	}
+	// the antimeridian starts regions at -180 and ends them at 180
+	if bounds.West == 180 {
+		bounds.West = -180
+	}
+	if bounds.East == -180 {
+		bounds.East = 180
+	}
+	return bounds, nil
+}
+
+// Width returns the east-west extent of the bounds in degrees, accounting for the antimeridian.
+func (b Bounds) Width() float64 {
+	width := b.East - b.West
+	if width <= 0 {
+		width += 360
+	}
+	return width
+}
+
+// Height returns the north-south extent of the bounds in degrees.
+func (b Bounds) Height() float64 {
+	return b.North - b.South
+}
+
+// Contains reports whether a position lies within the bounds.
+func (b Bounds) Contains(p LatLng) bool {
+	if p.Lat < b.South || p.Lat > b.North {
+		return false
+	}
+	offset := NormalizeLng(p.Lng) - NormalizeLng(b.West)
+	if offset < 0 {
+		offset += 360
+	}
+	return offset <= b.Width()
+}
+
+// At returns the position a fraction of the way across the bounds from the north-west corner, with fx running
+// east and fy running south.
+func (b Bounds) At(fx, fy float64) LatLng {
+	return LatLng{
+		Lat: b.North - fy*b.Height(),
+		Lng: NormalizeLng(b.West + fx*b.Width()),
+	}
+}
@@ -0,0 +1,284 @@
+package gebco
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+// GeoJsonFeatureCollection is an RFC 7946 GeoJSON feature collection.
+type GeoJsonFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJsonFeature `json:"features"`
+}
+
+// GeoJsonFeature is a GeoJSON feature with a geometry and free-form properties.
+type GeoJsonFeature struct {
+	Type       string          `json:"type"`
+	Geometry   GeoJsonGeometry `json:"geometry"`
+	Properties map[string]any  `json:"properties"`
+}
+
+// GeoJsonGeometry is a GeoJSON geometry. Coordinates nest [lng, lat] positions as deeply as the type requires.
+type GeoJsonGeometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+// NewGeoJsonFeature creates a feature from a geometry and its properties.
+func NewGeoJsonFeature(geometry GeoJsonGeometry, properties map[string]any) GeoJsonFeature {
+	return GeoJsonFeature{Type: "Feature", Geometry: geometry, Properties: properties}
+}
+
+// WriteGeoJson writes the features as an indented GeoJSON feature collection.
+func WriteGeoJson(w io.Writer, features []GeoJsonFeature) error {
+	if features == nil {
+		features = []GeoJsonFeature{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(GeoJsonFeatureCollection{Type: "FeatureCollection", Features: features})
+}
+
+// geoJsonObject holds the members of any GeoJSON object needed to find the polygons within it.
+type geoJsonObject struct {
+	Type        string          `json:"type"`
+	Features    []geoJsonObject `json:"features"`
+	Geometry    *geoJsonObject  `json:"geometry"`
+	Geometries  []geoJsonObject `json:"geometries"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// ReadGeoJsonPolygons reads the Polygon and MultiPolygon geometries of a GeoJSON feature collection, feature or
+// geometry as polygons, turning their rings to run anticlockwise around exteriors and clockwise around holes.
+// Other geometries are ignored.
+func ReadGeoJsonPolygons(r io.Reader) ([]Polygon, error) {
+	var object geoJsonObject
+	if err := json.NewDecoder(r).Decode(&object); err != nil {
+		return nil, fmt.Errorf("failed to decode GeoJSON: %w", err)
+	}
+	polygons := []Polygon{}
+	var collect func(object geoJsonObject) error
+	collect = func(object geoJsonObject) error {
+		switch object.Type {
+		case "FeatureCollection":
+			for _, feature := range object.Features {
+				if err := collect(feature); err != nil {
+					return err
+				}
+			}
+		case "Feature":
+			if object.Geometry != nil {
+				return collect(*object.Geometry)
+			}
+		case "GeometryCollection":
+			for _, geometry := range object.Geometries {
+				if err := collect(geometry); err != nil {
+					return err
+				}
+			}
+		case "Polygon":
+			var rings [][][]float64
+			if err := json.Unmarshal(object.Coordinates, &rings); err != nil {
+				return fmt.Errorf("invalid Polygon coordinates: %w", err)
+			}
+			polygon, err := geoJsonPolygon(rings)
+			if err != nil {
+				return err
+			}
+			polygons = append(polygons, polygon)
+		case "MultiPolygon":
+			var parts [][][][]float64
+			if err := json.Unmarshal(object.Coordinates, &parts); err != nil {
+				return fmt.Errorf("invalid MultiPolygon coordinates: %w", err)
+			}
+			for _, rings := range parts {
+				polygon, err := geoJsonPolygon(rings)
+				if err != nil {
+					return err
+				}
+				polygons = append(polygons, polygon)
+			}
+		}
+		return nil
+	}
+	if err := collect(object); err != nil {
+		return nil, err
+	}
+	return polygons, nil
+}
+
+// geoJsonPolygon converts the rings of GeoJSON Polygon coordinates to a polygon.
+func geoJsonPolygon(rings [][][]float64) (Polygon, error) {
+	if len(rings) == 0 {
+		return Polygon{}, fmt.Errorf("invalid Polygon without an exterior ring")
+	}
+	polygon := Polygon{Holes: [][]LatLng{}}
+	for i, coordinates := range rings {
+		if len(coordinates) < 4 {
+			return Polygon{}, fmt.Errorf("invalid Polygon ring of %d positions, expected at least 4", len(coordinates))
+		}
+		ring := make([]LatLng, len(coordinates))
+		for j, position := range coordinates {
+			if len(position) < 2 {
+				return Polygon{}, fmt.Errorf("invalid position %v, expected [lng, lat]", position)
+			}
+			ring[j] = LatLng{Lat: position[1], Lng: position[0]}
+		}
+		if area := RingArea(ring); (i == 0) != (area > 0) {
+			slices.Reverse(ring)
+		}
+		if i == 0 {
+			polygon.Exterior = ring
+		} else {
+			polygon.Holes = append(polygon.Holes, ring)
+		}
+		polygon.Area += RingArea(ring)
+	}
+	return polygon, nil
+}
+
+// PointGeometry converts a position to a Point with its longitude in [-180, 180).
+func PointGeometry(p LatLng) GeoJsonGeometry {
+	return GeoJsonGeometry{Type: "Point", Coordinates: [2]float64{NormalizeLng(p.Lng), p.Lat}}
+}
+
+// LineStringGeometry converts a line of positions with unwrapped longitudes (consecutive positions never more
+// than 180 degrees apart, but possibly outside [-180, 180)) to a LineString, or a MultiLineString split at the
+// antimeridian if it crosses it, as RFC 7946 recommends.
+func LineStringGeometry(points []LatLng) GeoJsonGeometry {
+	parts := splitAntimeridian(points)
+	if len(parts) == 1 {
+		return GeoJsonGeometry{Type: "LineString", Coordinates: parts[0]}
+	}
+	return GeoJsonGeometry{Type: "MultiLineString", Coordinates: parts}
+}
+
+// splitAntimeridian splits a line with unwrapped longitudes into parts with longitudes in [-180, 180], ending
+// and starting each part on the antimeridian where the line crosses it.
+func splitAntimeridian(points []LatLng) [][][2]float64 {
+	parts := [][][2]float64{}
+	part := [][2]float64{}
+	band := func(lng float64) float64 {
+		return math.Floor((lng + 180) / 360)
+	}
+	for i, p := range points {
+		if i > 0 {
+			previous := points[i-1]
+			previousBand, currentBand := band(previous.Lng), band(p.Lng)
+			if currentBand != previousBand {
+				// the boundary between the bands, at an odd multiple of 180 degrees
+				boundary := 360*max(previousBand, currentBand) - 180
+				t := (boundary - previous.Lng) / (p.Lng - previous.Lng)
+				lat := previous.Lat + t*(p.Lat-previous.Lat)
+				part = append(part, [2]float64{boundary - 360*previousBand, lat})
+				if len(part) > 1 {
+					parts = append(parts, part)
+				}
+				part = [][2]float64{{boundary - 360*currentBand, lat}}
+			}
+		}
+		part = append(part, [2]float64{p.Lng - 360*band(p.Lng), p.Lat})
+	}
+	if len(part) > 1 || len(parts) == 0 {
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// PolygonGeometry converts a polygon with unwrapped longitudes to a Polygon, or a MultiPolygon cut at the
+// antimeridian if it crosses it, as RFC 7946 recommends. Rings must repeat their first position last, exteriors
+// should run anticlockwise and holes clockwise.
+func PolygonGeometry(exterior []LatLng, holes [][]LatLng) GeoJsonGeometry {
+	// shift the polygon so that it starts east of -180, with its holes alongside the exterior
+	minLng, maxLng := lngRange(exterior)
+	shift := -360 * math.Floor((minLng+180)/360)
+	centre := (minLng + maxLng) / 2
+	shifted := func(ring []LatLng, shift float64) []LatLng {
+		result := make([]LatLng, len(ring))
+		for i, p := range ring {
+			result[i] = LatLng{Lat: p.Lat, Lng: p.Lng + shift}
+		}
+		return result
+	}
+	rings := [][]LatLng{shifted(exterior, shift)}
+	for _, hole := range holes {
+		holeMin, holeMax := lngRange(hole)
+		holeShift := 360 * math.Round((centre-(holeMin+holeMax)/2)/360)
+		rings = append(rings, shifted(hole, shift+holeShift))
+	}
+	maxLng += shift
+
+	if maxLng <= 180 {
+		return GeoJsonGeometry{Type: "Polygon", Coordinates: ringCoordinates(rings, 0)}
+	}
+	west, east := [][]LatLng{}, [][]LatLng{}
+	for _, ring := range rings {
+		if clipped := clipRing(ring, 180, true); len(clipped) > 3 {
+			west = append(west, clipped)
+		}
+		if clipped := clipRing(ring, 180, false); len(clipped) > 3 {
+			east = append(east, clipped)
+		}
+	}
+	polygons := [][][][2]float64{}
+	if len(west) > 0 {
+		polygons = append(polygons, ringCoordinates(west, 0))
+	}
+	if len(east) > 0 {
+		polygons = append(polygons, ringCoordinates(east, -360))
+	}
+	if len(polygons) == 1 {
+		return GeoJsonGeometry{Type: "Polygon", Coordinates: polygons[0]}
+	}
+	return GeoJsonGeometry{Type: "MultiPolygon", Coordinates: polygons}
+}
+
+// lngRange returns the smallest and largest longitude of a ring.
+func lngRange(ring []LatLng) (float64, float64) {
+	minLng, maxLng := math.Inf(1), math.Inf(-1)
+	for _, p := range ring {
+		minLng, maxLng = min(minLng, p.Lng), max(maxLng, p.Lng)
+	}
+	return minLng, maxLng
+}
+
+// ringCoordinates converts rings to GeoJSON positions, shifting their longitudes.
+func ringCoordinates(rings [][]LatLng, shift float64) [][][2]float64 {
+	coordinates := make([][][2]float64, len(rings))
+	for i, ring := range rings {
+		coordinates[i] = make([][2]float64, len(ring))
+		for j, p := range ring {
+			coordinates[i][j] = [2]float64{p.Lng + shift, p.Lat}
+		}
+	}
+	return coordinates
+}
+
+// clipRing clips a closed ring to the half of the plane west (or east) of a meridian with the Sutherland-Hodgman
+// algorithm, returning a closed ring that runs along the meridian where the ring was cut.
+func clipRing(ring []LatLng, lng float64, west bool) []LatLng {
+	inside := func(p LatLng) bool {
+		if west {
+			return p.Lng <= lng
+		}
+		return p.Lng >= lng
+	}
+	clipped := []LatLng{}
+	for i := 1; i < len(ring); i++ {
+		a, b := ring[i-1], ring[i]
+		if inside(a) != inside(b) {
+			t := (lng - a.Lng) / (b.Lng - a.Lng)
+			clipped = append(clipped, LatLng{Lat: a.Lat + t*(b.Lat-a.Lat), Lng: lng})
+		}
+		if inside(b) {
+			clipped = append(clipped, b)
+		}
+	}
+	if len(clipped) > 0 && clipped[0] != clipped[len(clipped)-1] {
+		clipped = append(clipped, clipped[0])
+	}
+	return clipped
+}
@@ -0,0 +1,307 @@
+package gebco
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io"
+	"math"
+	"slices"
+)
+
+// TIFF field types.
+const (
+	tiffAscii  uint16 = 2
+	tiffShort  uint16 = 3
+	tiffLong   uint16 = 4
+	tiffDouble uint16 = 12
+	tiffLong8  uint16 = 16
+)
+
+// TIFF and GeoTIFF tags.
+const (
+	tagImageWidth                uint16 = 256
+	tagImageLength               uint16 = 257
+	tagBitsPerSample             uint16 = 258
+	tagCompression               uint16 = 259
+	tagPhotometricInterpretation uint16 = 262
+	tagStripOffsets              uint16 = 273
+	tagSamplesPerPixel           uint16 = 277
+	tagRowsPerStrip              uint16 = 278
+	tagStripByteCounts           uint16 = 279
+	tagPlanarConfiguration       uint16 = 284
+	tagExtraSamples              uint16 = 338
+	tagSampleFormat              uint16 = 339
+	tagModelPixelScale           uint16 = 33550
+	tagModelTiepoint             uint16 = 33922
+	tagGeoKeyDirectory           uint16 = 34735
+	tagGdalNoData                uint16 = 42113
+)
+
+const (
+	tiffCompressionDeflate  uint16 = 8
+	tiffSampleFormatFloat   uint16 = 3
+	geoTiffStripRows        int    = 16
+	geoKeyModelType         uint16 = 1024
+	geoKeyRasterType        uint16 = 1025
+	geoKeyGeographicType    uint16 = 2048
+	geoKeyProjectedType     uint16 = 3072
+	geoModelTypeProjected   uint16 = 1
+	geoModelTypeGeographic  uint16 = 2
+	geoRasterTypePixelArea  uint16 = 1
+	geoKeyDirectoryVersion  uint16 = 1
+	geoKeyDirectoryRevision uint16 = 1
+)
+
+// tiffField is a single IFD entry with its values already encoded little-endian.
+type tiffField struct {
+	tag   uint16
+	kind  uint16
+	count uint64
+	data  []byte
+}
+
+func shortsField(tag uint16, values ...uint16) tiffField {
+	data := make([]byte, 2*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint16(data[2*i:], v)
+	}
+	return tiffField{tag: tag, kind: tiffShort, count: uint64(len(values)), data: data}
+}
+
+func longsField(tag uint16, values ...uint32) tiffField {
+	data := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(data[4*i:], v)
+	}
+	return tiffField{tag: tag, kind: tiffLong, count: uint64(len(values)), data: data}
+}
+
+func doublesField(tag uint16, values ...float64) tiffField {
+	data := make([]byte, 8*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint64(data[8*i:], math.Float64bits(v))
+	}
+	return tiffField{tag: tag, kind: tiffDouble, count: uint64(len(values)), data: data}
+}
+
+func asciiField(tag uint16, value string) tiffField {
+	data := append([]byte(value), 0)
+	return tiffField{tag: tag, kind: tiffAscii, count: uint64(len(data)), data: data}
+}
+
+// geoFields returns the GeoTIFF fields locating a north-up raster with the given top-left corner and pixel size
+// in a projection.
+func geoFields(projection Projection, minX, maxY, pixelWidth, pixelHeight float64) []tiffField {
+	keys := []uint16{geoKeyDirectoryVersion, geoKeyDirectoryRevision, 0, 3}
+	if projection.EPSG() == 4326 {
+		keys = append(keys,
+			geoKeyModelType, 0, 1, geoModelTypeGeographic,
+			geoKeyRasterType, 0, 1, geoRasterTypePixelArea,
+			geoKeyGeographicType, 0, 1, uint16(projection.EPSG()))
+	} else {
+		keys = append(keys,
+			geoKeyModelType, 0, 1, geoModelTypeProjected,
+			geoKeyRasterType, 0, 1, geoRasterTypePixelArea,
+			geoKeyProjectedType, 0, 1, uint16(projection.EPSG()))
+	}
+	return []tiffField{
+		doublesField(tagModelPixelScale, pixelWidth, pixelHeight, 0),
+		doublesField(tagModelTiepoint, 0, 0, 0, minX, maxY, 0),
+		shortsField(tagGeoKeyDirectory, keys...),
+	}
+}
+
+// tiffLayout describes the offset width of a classic TIFF or a BigTIFF file.
+type tiffLayout struct {
+	big bool
+}
+
+// headerSize returns the size of the file header.
+func (l tiffLayout) headerSize() uint64 {
+	if l.big {
+		return 16
+	}
+	return 8
+}
+
+// header returns the little-endian file header pointing at the first IFD.
+func (l tiffLayout) header(firstIfd uint64) []byte {
+	if l.big {
+		header := []byte{'I', 'I', 43, 0, 8, 0, 0, 0}
+		return binary.LittleEndian.AppendUint64(header, firstIfd)
+	}
+	header := []byte{'I', 'I', 42, 0}
+	return binary.LittleEndian.AppendUint32(header, uint32(firstIfd))
+}
+
+// offsetsField returns a field of file offsets or byte counts, as LONG in a classic TIFF and LONG8 in a BigTIFF.
+func (l tiffLayout) offsetsField(tag uint16, values []uint64) tiffField {
+	if !l.big {
+		longs := make([]uint32, len(values))
+		for i, v := range values {
+			longs[i] = uint32(v)
+		}
+		return longsField(tag, longs...)
+	}
+	data := make([]byte, 8*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint64(data[8*i:], v)
+	}
+	return tiffField{tag: tag, kind: tiffLong8, count: uint64(len(values)), data: data}
+}
+
+// entrySizes returns the size of the IFD entry count, an entry, an inline value and the next IFD offset.
+func (l tiffLayout) entrySizes() (count, entry, inline, next int) {
+	if l.big {
+		return 8, 20, 8, 8
+	}
+	return 2, 12, 4, 4
+}
+
+// ifdSize returns the number of bytes writeIfd writes for the fields.
+func (l tiffLayout) ifdSize(fields []tiffField) uint64 {
+	count, entry, inline, next := l.entrySizes()
+	size := uint64(count + entry*len(fields) + next)
+	for _, field := range fields {
+		if len(field.data) > inline {
+			size += uint64(len(field.data) + len(field.data)%2)
+		}
+	}
+	return size
+}
+
+// writeIfd writes an IFD at offset, followed immediately by any values too large to fit in their entries, and
+// returns the offset just past the written data.
+func (l tiffLayout) writeIfd(w io.Writer, offset uint64, fields []tiffField, nextIfd uint64) (uint64, error) {
+	slices.SortFunc(fields, func(a, b tiffField) int {
+		return int(a.tag) - int(b.tag)
+	})
+
+	count, entry, inline, next := l.entrySizes()
+	external := offset + uint64(count+entry*len(fields)+next)
+	putOffset := func(buf []byte, v uint64) []byte {
+		if l.big {
+			return binary.LittleEndian.AppendUint64(buf, v)
+		}
+		return binary.LittleEndian.AppendUint32(buf, uint32(v))
+	}
+
+	ifd := putOffset(nil, uint64(len(fields)))[:count]
+	extra := []byte{}
+	for _, field := range fields {
+		ifd = binary.LittleEndian.AppendUint16(ifd, field.tag)
+		ifd = binary.LittleEndian.AppendUint16(ifd, field.kind)
+		ifd = putOffset(ifd, field.count)
+		if len(field.data) <= inline {
+			value := make([]byte, inline)
+			copy(value, field.data)
+			ifd = append(ifd, value...)
+		} else {
+			ifd = putOffset(ifd, external+uint64(len(extra)))
+			extra = append(extra, field.data...)
+			if len(extra)%2 == 1 {
+				extra = append(extra, 0) // keep values word aligned
+			}
+		}
+	}
+	ifd = putOffset(ifd, nextIfd)
+
+	if _, err := w.Write(ifd); err != nil {
+		return 0, err
+	}
+	if _, err := w.Write(extra); err != nil {
+		return 0, err
+	}
+	return external + uint64(len(extra)), nil
+}
+
+func deflate(data []byte) ([]byte, error) {
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return compressed.Bytes(), nil
+}
+
+// WriteGeoTiff writes the raster as a deflate compressed, pixel interleaved float32 GeoTIFF with NaN as the no
+// data value.
+func (r *Raster) WriteGeoTiff(w io.Writer) error {
+	layout := tiffLayout{}
+	bands := len(r.Bands)
+
+	// compress the strips up front, since their sizes are needed for the IFD
+	strips := [][]byte{}
+	for stripStart := 0; stripStart < r.Height; stripStart += geoTiffStripRows {
+		stripEnd := min(r.Height, stripStart+geoTiffStripRows)
+		raw := make([]byte, 0, (stripEnd-stripStart)*r.Width*bands*4)
+		for j := stripStart; j < stripEnd; j++ {
+			for i := range r.Width {
+				for band := range bands {
+					raw = binary.LittleEndian.AppendUint32(raw, math.Float32bits(r.Bands[band][j*r.Width+i]))
+				}
+			}
+		}
+		strip, err := deflate(raw)
+		if err != nil {
+			return err
+		}
+		strips = append(strips, strip)
+	}
+
+	bitsPerSample := make([]uint16, bands)
+	sampleFormat := make([]uint16, bands)
+	for band := range bands {
+		bitsPerSample[band] = 32
+		sampleFormat[band] = tiffSampleFormatFloat
+	}
+	stripCounts := make([]uint64, len(strips))
+	for i, strip := range strips {
+		stripCounts[i] = uint64(len(strip))
+	}
+
+	fields := []tiffField{
+		longsField(tagImageWidth, uint32(r.Width)),
+		longsField(tagImageLength, uint32(r.Height)),
+		shortsField(tagBitsPerSample, bitsPerSample...),
+		shortsField(tagCompression, tiffCompressionDeflate),
+		shortsField(tagPhotometricInterpretation, 1),
+		shortsField(tagSamplesPerPixel, uint16(bands)),
+		longsField(tagRowsPerStrip, uint32(geoTiffStripRows)),
+		layout.offsetsField(tagStripByteCounts, stripCounts),
+		shortsField(tagPlanarConfiguration, 1),
+		shortsField(tagSampleFormat, sampleFormat...),
+		asciiField(tagGdalNoData, "nan"),
+	}
+	if bands > 1 {
+		fields = append(fields, shortsField(tagExtraSamples, make([]uint16, bands-1)...))
+	}
+	fields = append(fields, geoFields(r.Projection, r.MinX, r.MaxY, r.PixelWidth, r.PixelHeight)...)
+
+	// the strip offsets field has a fixed size, so the data start is known before the offsets are
+	stripOffsets := make([]uint64, len(strips))
+	fields = append(fields, layout.offsetsField(tagStripOffsets, stripOffsets))
+	offset := layout.headerSize() + layout.ifdSize(fields)
+	for i, strip := range strips {
+		stripOffsets[i] = offset
+		offset += uint64(len(strip))
+	}
+	fields[len(fields)-1] = layout.offsetsField(tagStripOffsets, stripOffsets)
+
+	if _, err := w.Write(layout.header(layout.headerSize())); err != nil {
+		return err
+	}
+	if _, err := layout.writeIfd(w, layout.headerSize(), fields, 0); err != nil {
+		return err
+	}
+	for _, strip := range strips {
+		if _, err := w.Write(strip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
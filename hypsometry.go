@@ -0,0 +1,161 @@
+package gebco
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"slices"
+)
+
+// HypsometryOptions configures Hypsometry.
+type HypsometryOptions struct {
+	Region   Bounds        // The region to summarise, expanded to whole pixels of the grid.
+	Polygons []Polygon     // If any, only the pixels of the region whose centres lie inside one of them are summarised.
+	Surface  GebcoDataType // The surface (ice, sub-ice) to summarise.
+	BinSize  float64       // The height in metres of bins aligned to multiples of it, used when there are no edges.
+	Edges    []float64     // The ascending elevations in metres bounding the bins. Pixels outside them are left out.
+	Workers  int           // The number of goroutines reading tiles, or 0 for one per processor.
+}
+
+// HypsometryBin counts the pixels whose elevation lies within a bin.
+type HypsometryBin struct {
+	Min           float64 `json:"min"` // The elevation in metres of the bottom of the bin, inclusive.
+	Max           float64 `json:"max"` // The elevation in metres of the top of the bin, exclusive.
+	Pixels        int     `json:"pixels"`
+	Area          float64 `json:"area"`          // The area in square metres of the pixels in the bin.
+	AreaAbove     float64 `json:"areaAbove"`     // The area in square metres of the pixels at or above the bottom of the bin.
+	FractionAbove float64 `json:"fractionAbove"` // The fraction of the total area at or above the bottom of the bin.
+}
+
+// HypsometricCurve is the area-weighted histogram of the elevations of a region, with the cumulative area above
+// each bin tracing its hypsometric curve.
+type HypsometricCurve struct {
+	Pixels int             `json:"pixels"`
+	Area   float64         `json:"area"` // The area in square metres of the pixels in the bins.
+	Bins   []HypsometryBin `json:"bins"` // Ordered from the lowest elevation up.
+}
+
+// hypsometryCount is the number and area of the pixels counted in a bin.
+type hypsometryCount struct {
+	pixels int
+	area   float64
+}
+
+// Hypsometry computes the hypsometric curve of a region, or of the parts of it inside a set of polygons, in a
+// single pass over the tiles of the grid read in parallel. With a bin size, the bins run from the lowest to the
+// highest elevation found, including any empty bins between.
+func Hypsometry(grid *Grid, opts HypsometryOptions) (*HypsometricCurve, error) {
+	if opts.Surface == GebcoDataTypeId {
+		return nil, fmt.Errorf("cannot compute the hypsometry of type IDs")
+	}
+	if len(opts.Edges) > 0 {
+		ascending := len(opts.Edges) >= 2
+		for i := 1; i < len(opts.Edges); i++ {
+			ascending = ascending && opts.Edges[i] > opts.Edges[i-1]
+		}
+		if !ascending {
+			return nil, fmt.Errorf("invalid bin edges %v: expected at least 2 strictly ascending elevations", opts.Edges)
+		}
+	} else if opts.BinSize <= 0 {
+		return nil, fmt.Errorf("invalid bin size %g", opts.BinSize)
+	}
+	bin := func(elevation float64) (int, bool) {
+		if len(opts.Edges) == 0 {
+			return int(math.Floor(elevation / opts.BinSize)), true
+		}
+		i, found := slices.BinarySearch(opts.Edges, elevation)
+		if found {
+			return i, i < len(opts.Edges)-1
+		}
+		return i - 1, i > 0 && i < len(opts.Edges)
+	}
+	total, err := countElevations(grid, opts.Region, opts.Polygons, opts.Surface, opts.Workers, bin)
+	if err != nil {
+		return nil, err
+	}
+
+	curve := &HypsometricCurve{Bins: []HypsometryBin{}}
+	first, last := 0, len(opts.Edges)-2
+	if len(opts.Edges) == 0 {
+		first, last = math.MaxInt, math.MinInt
+		for index := range total {
+			first, last = min(first, index), max(last, index)
+		}
+	}
+	for index := first; index <= last; index++ {
+		b := HypsometryBin{Pixels: total[index].pixels, Area: total[index].area}
+		if len(opts.Edges) == 0 {
+			b.Min, b.Max = float64(index)*opts.BinSize, float64(index+1)*opts.BinSize
+		} else {
+			b.Min, b.Max = opts.Edges[index], opts.Edges[index+1]
+		}
+		curve.Bins = append(curve.Bins, b)
+		curve.Pixels += b.Pixels
+	}
+	// sum the area in the order it is accumulated so that the lowest bin has all of it above
+	for i := len(curve.Bins) - 1; i >= 0; i-- {
+		curve.Area += curve.Bins[i].Area
+		curve.Bins[i].AreaAbove = curve.Area
+	}
+	for i := range curve.Bins {
+		if curve.Area > 0 {
+			curve.Bins[i].FractionAbove = curve.Bins[i].AreaAbove / curve.Area
+		}
+	}
+	return curve, nil
+}
+
+// countElevations counts the pixels of a region, or of the parts of it inside a set of polygons, and their areas by
+// the index a bin function gives their elevation, skipping those it rejects, in a single pass over the tiles of the
+// grid read in parallel by a number of workers, or one per processor.
+func countElevations(grid *Grid, bounds Bounds, polygons []Polygon, surface GebcoDataType, workers int, bin func(elevation float64) (int, bool)) (map[int]hypsometryCount, error) {
+	region, err := grid.Region(bounds)
+	if err != nil {
+		return nil, err
+	}
+	var mask *polygonMask
+	if len(polygons) > 0 {
+		mask = newPolygonMask(region, polygons)
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	counts := make([]map[int]hypsometryCount, workers)
+	for worker := range counts {
+		counts[worker] = map[int]hypsometryCount{}
+	}
+	step := grid.DegreesPerPixel()
+	err = region.EachTile(workers, func(worker int, part GridRegion, samples []GebcoSample) error {
+		for j := range part.Height {
+			area := CellArea(grid.PixelCenter(0, part.Y+j).Lat, step)
+			for i := range part.Width {
+				if mask != nil && !mask.Contains(part.X-region.X+i, part.Y-region.Y+j) {
+					continue
+				}
+				index, ok := bin(samples[part.Index(i, j)].Value(surface))
+				if !ok {
+					continue
+				}
+				count := counts[worker][index]
+				count.pixels++
+				count.area += area
+				counts[worker][index] = count
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	total := map[int]hypsometryCount{}
+	for _, workerCounts := range counts {
+		for index, count := range workerCounts {
+			sum := total[index]
+			sum.pixels += count.pixels
+			sum.area += count.area
+			total[index] = sum
+		}
+	}
+	return total, nil
+}
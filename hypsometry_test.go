@@ -0,0 +1,122 @@
+package gebco
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestHypsometry(t *testing.T) {
+	// rows of 10 degree pixels falling 500 m at a time from 1000 m in the north
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		z := int16(1000 - 500*y)
+		return GebcoSample{Ice: z, SubIce: z, Tid: GebcoTypeMultiBeam}
+	})
+	grid := dataset.Grid()
+
+	curve, err := Hypsometry(grid, HypsometryOptions{Region: GlobalBounds, Surface: GebcoDataIce, BinSize: 1000, Workers: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if curve.Pixels != 36*18 || math.Abs(curve.Area/(4*math.Pi*EarthRadius*EarthRadius)-1) > 0.01 {
+		t.Errorf("expected the whole globe, got %d pixels of %g square metres", curve.Pixels, curve.Area)
+	}
+	if len(curve.Bins) != 10 || curve.Bins[0].Min != -8000 || curve.Bins[9].Max != 2000 {
+		t.Fatalf("expected 10 bins from -8000 to 2000, got %+v", curve.Bins)
+	}
+	if curve.Bins[9].Pixels != 36 || curve.Bins[0].Pixels != 36 || curve.Bins[5].Pixels != 72 {
+		t.Errorf("unexpected bin counts %+v", curve.Bins)
+	}
+	if curve.Bins[0].FractionAbove != 1 || curve.Bins[0].AreaAbove != curve.Area || curve.Bins[9].AreaAbove != curve.Bins[9].Area {
+		t.Errorf("expected the cumulative area to run from the top bin to the whole area, got %+v", curve.Bins)
+	}
+
+	// explicit edges leave out the pixels outside them, including those on the last edge
+	curve, err = Hypsometry(grid, HypsometryOptions{Region: GlobalBounds, Surface: GebcoDataIce, Edges: []float64{-1000, 0, 1000}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(curve.Bins) != 2 || curve.Bins[0].Pixels != 72 || curve.Bins[1].Pixels != 72 || curve.Pixels != 144 {
+		t.Errorf("expected 72 pixels in each of 2 bins, got %+v", curve.Bins)
+	}
+	if _, err := Hypsometry(grid, HypsometryOptions{Region: GlobalBounds, Edges: []float64{0, 0}}); err == nil {
+		t.Errorf("expected an error for bin edges that do not ascend")
+	}
+
+	// polygons around the equator at the prime meridian, with a hole, and across the antimeridian
+	polygons, err := ReadGeoJsonPolygons(strings.NewReader(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "properties": {}, "geometry": {"type": "Polygon", "coordinates": [
+			[[-30, -30], [-30, 30], [30, 30], [30, -30], [-30, -30]],
+			[[-10, -10], [10, -10], [10, 10], [-10, 10], [-10, -10]]]}},
+		{"type": "Feature", "properties": {}, "geometry": {"type": "MultiPolygon", "coordinates": [
+			[[[170, -10], [190, -10], [190, 10], [170, 10], [170, -10]]]]}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(polygons) != 2 || len(polygons[0].Holes) != 1 || polygons[0].Area <= 0 || RingArea(polygons[0].Holes[0]) >= 0 {
+		t.Fatalf("expected a polygon with a hole and a polygon across the antimeridian, got %+v", polygons)
+	}
+	if bounds := PolygonsBounds(polygons); bounds != (Bounds{West: -30, South: -30, East: -170, North: 30}) {
+		t.Errorf("expected bounds from the prime meridian polygon across the antimeridian, got %+v", bounds)
+	}
+	for _, c := range []struct {
+		lngs   [][2]float64
+		bounds Bounds
+	}{
+		{[][2]float64{{10, 20}}, Bounds{West: 10, South: -1, East: 20, North: 1}},
+		{[][2]float64{{170, 190}}, Bounds{West: 170, South: -1, East: -170, North: 1}},
+		{[][2]float64{{-190, -170}, {-160, -150}}, Bounds{West: 170, South: -1, East: -150, North: 1}},
+		{[][2]float64{{170, 250}, {-150, -140}}, Bounds{West: 170, South: -1, East: -110, North: 1}},
+		{[][2]float64{{160, 180}, {0, 10}}, Bounds{West: 0, South: -1, East: 180, North: 1}},
+		{[][2]float64{{-180, 0}, {0, 180}}, Bounds{West: -180, South: -1, East: 180, North: 1}},
+	} {
+		strips := []Polygon{}
+		for _, lngs := range c.lngs {
+			strips = append(strips, Polygon{Exterior: []LatLng{
+				{Lat: -1, Lng: lngs[0]}, {Lat: -1, Lng: lngs[1]}, {Lat: 1, Lng: lngs[1]}, {Lat: 1, Lng: lngs[0]}, {Lat: -1, Lng: lngs[0]}}})
+		}
+		if bounds := PolygonsBounds(strips); bounds != c.bounds {
+			t.Errorf("expected bounds %+v for polygons spanning %v, got %+v", c.bounds, c.lngs, bounds)
+		}
+	}
+	curve, err = Hypsometry(grid, HypsometryOptions{Region: PolygonsBounds(polygons), Polygons: polygons, Surface: GebcoDataIce, BinSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if curve.Pixels != 32+4 {
+		t.Errorf("expected 32 pixels around the hole and 4 across the antimeridian, got %d", curve.Pixels)
+	}
+}
+
+func TestGridRegionTiles(t *testing.T) {
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		return GebcoSample{Ice: int16(x), SubIce: int16(y), Tid: GebcoTypeMultiBeam}
+	})
+	region, err := dataset.Grid().Region(Bounds{West: 150, South: -40, East: -150, North: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := region.Tiles()
+	if len(parts) != 4 || parts[0].X != 33 || parts[0].Width != 3 || parts[1].X != 36 || parts[1].Width != 3 || parts[0].Height != 5 || parts[3].Height != 1 {
+		t.Fatalf("expected the region split at the antimeridian and a tile row into 4 parts, got %+v", parts)
+	}
+
+	stop := errors.New("stop")
+	err = region.EachTile(2, func(worker int, part GridRegion, samples []GebcoSample) error {
+		for j := range part.Height {
+			for i := range part.Width {
+				if sample := samples[part.Index(i, j)]; int(sample.Ice) != (part.X+i)%36 || int(sample.SubIce) != part.Y+j {
+					t.Errorf("unexpected sample %+v at (%d,%d)", sample, part.X+i, part.Y+j)
+				}
+			}
+		}
+		if part.X == 36 && part.Y == 12 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("expected the error to stop the pass, got %v", err)
+	}
+}
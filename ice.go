@@ -0,0 +1,150 @@
+package gebco
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gracefulearth/gopixi"
+)
+
+const (
+	IceDensity      float64 = 917  // The density of glacial ice in kg/m³.
+	SeawaterDensity float64 = 1027 // The density of seawater in kg/m³.
+)
+
+// IceClass classifies the surface of a GEBCO cell by its ice cover.
+type IceClass uint8
+
+const (
+	IceOpenWater IceClass = iota // Sea without ice cover.
+	IceGrounded                  // Ice resting on the ground or seafloor.
+	IceFloating                  // An ice shelf floating on the sea, with water between its base and the seafloor.
+	IceBareLand                  // Land without ice cover.
+)
+
+// MarshalText returns the name of the ice class.
+func (c IceClass) MarshalText() ([]byte, error) {
+	switch c {
+	case IceOpenWater:
+		return []byte("open-water"), nil
+	case IceGrounded:
+		return []byte("grounded"), nil
+	case IceFloating:
+		return []byte("floating"), nil
+	case IceBareLand:
+		return []byte("bare-land"), nil
+	default:
+		return nil, fmt.Errorf("unknown IceClass %d", c)
+	}
+}
+
+// String returns the name of the ice class.
+func (c IceClass) String() string {
+	name, err := c.MarshalText()
+	if err != nil {
+		return err.Error()
+	}
+	return string(name)
+}
+
+// IceGeometry describes the ice cover of a GEBCO cell.
+type IceGeometry struct {
+	Class     IceClass `json:"class"`
+	Thickness float64  `json:"thickness"` // The thickness of the ice in metres, zero without ice cover.
+}
+
+// IceGeometry derives the ice cover of a sample from the difference between its ice surface and the ground or
+// seafloor beneath. Where that column is thicker than ice with the same freeboard would be when floating in
+// hydrostatic equilibrium, there must be water beneath, so the ice is a floating shelf of the hydrostatic
+// thickness; otherwise the whole column is grounded ice.
+func (s GebcoSample) IceGeometry() IceGeometry {
+	column := float64(s.Ice) - float64(s.SubIce)
+	if column <= 0 {
+		if s.IsLand() {
+			return IceGeometry{Class: IceBareLand}
+		}
+		return IceGeometry{Class: IceOpenWater}
+	}
+
+	if s.Ice > 0 && s.SubIce < 0 {
+		floating := float64(s.Ice) * SeawaterDensity / (SeawaterDensity - IceDensity)
+		if column > floating {
+			return IceGeometry{Class: IceFloating, Thickness: math.Round(floating)}
+		}
+	}
+	return IceGeometry{Class: IceGrounded, Thickness: column}
+}
+
+// IceLayerName names the layer of the ice geometry of every pixel of the full resolution layer, added to a Pixi
+// file by cmd/build with its ice-thickness and ice-class channels.
+const IceLayerName = "gebco_ice"
+
+// IceGrid provides access to an ice layer by pixel or geographic position, with the same pixels as the full
+// resolution layer it was derived from.
+type IceGrid struct {
+	access gopixi.TileAccessLayer
+	width  int
+	height int
+
+	thicknessChannel int
+	classChannel     int
+}
+
+// NewIceGrid wraps a Pixi layer accessor as an IceGrid, checking the layer has the shape and channels of an ice
+// layer.
+func NewIceGrid(access gopixi.TileAccessLayer) (*IceGrid, error) {
+	layer := access.Layer()
+	if len(layer.Dimensions) != 2 {
+		return nil, fmt.Errorf("layer %s has %d dimensions, expected 2", layer.Name, len(layer.Dimensions))
+	}
+	grid := &IceGrid{
+		access:           access,
+		width:            layer.Dimensions[0].Size,
+		height:           layer.Dimensions[1].Size,
+		thicknessChannel: layer.Channels.Index("ice-thickness"),
+		classChannel:     layer.Channels.Index("ice-class"),
+	}
+	if grid.thicknessChannel < 0 || grid.classChannel < 0 {
+		return nil, fmt.Errorf("layer %s is missing ice-thickness or ice-class channels", layer.Name)
+	}
+	if grid.width != 2*grid.height {
+		return nil, fmt.Errorf("layer %s is %dx%d, expected a global grid twice as wide as it is high", layer.Name, grid.width, grid.height)
+	}
+	return grid, nil
+}
+
+// Width returns the number of pixels along a strip of latitude.
+func (g *IceGrid) Width() int {
+	return g.width
+}
+
+// Height returns the number of pixels along a strip of longitude.
+func (g *IceGrid) Height() int {
+	return g.height
+}
+
+// Sample returns the ice geometry of a pixel. The x coordinate wraps around the antimeridian and the y coordinate
+// is clamped to the poles.
+func (g *IceGrid) Sample(x, y int) (IceGeometry, error) {
+	x %= g.width
+	if x < 0 {
+		x += g.width
+	}
+	y = max(0, min(g.height-1, y))
+	sample := make(gopixi.Sample, len(g.access.Layer().Channels))
+	if err := gopixi.SampleInto(g.access, gopixi.SampleCoordinate{x, y}, sample); err != nil {
+		return IceGeometry{}, fmt.Errorf("failed to read %s pixel (%d,%d): %w", g.access.Layer().Name, x, y, err)
+	}
+	return IceGeometry{
+		Class:     IceClass(sample[g.classChannel].(uint8)),
+		Thickness: float64(sample[g.thicknessChannel].(int16)),
+	}, nil
+}
+
+// SampleAt returns the ice geometry of the pixel containing a position.
+func (g *IceGrid) SampleAt(p LatLng) (IceGeometry, error) {
+	step := 360.0 / float64(g.width)
+	x := int(math.Floor((NormalizeLng(p.Lng) + 180) / step))
+	y := int(math.Floor((90 - p.Lat) / step))
+	return g.Sample(x, y)
+}
@@ -0,0 +1,104 @@
+package gebco
+
+import (
+	"testing"
+
+	"github.com/gracefulearth/gopixi"
+)
+
+func TestIceGeometry(t *testing.T) {
+	cases := []struct {
+		name              string
+		sample            GebcoSample
+		expectedClass     IceClass
+		expectedThickness float64
+	}{
+		{"open water", GebcoSample{Ice: -3000, SubIce: -3000, Tid: GebcoTypeSingleBeam}, IceOpenWater, 0},
+		{"bare land", GebcoSample{Ice: 250, SubIce: 250, Tid: GebcoTypeLand}, IceBareLand, 0},
+		{"ice sheet on land", GebcoSample{Ice: 2000, SubIce: 500, Tid: GebcoTypeLand}, IceGrounded, 1500},
+		{"ice grounded below sea level", GebcoSample{Ice: 50, SubIce: -250}, IceGrounded, 300},
+		{"ice shelf", GebcoSample{Ice: 50, SubIce: -600}, IceFloating, 467},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ice := c.sample.IceGeometry()
+			if ice.Class != c.expectedClass {
+				t.Errorf("expected class %s, got %s", c.expectedClass, ice.Class)
+			}
+			if ice.Thickness != c.expectedThickness {
+				t.Errorf("expected thickness %f, got %f", c.expectedThickness, ice.Thickness)
+			}
+		})
+	}
+}
+
+// iceTestLayer makes a gebco_ice layer for writeTestDataset derived from value as cmd/build does.
+func iceTestLayer(width int, tileSize int, value func(x, y int) GebcoSample) testLayer {
+	return testLayer{
+		layer: gopixi.NewLayer(IceLayerName,
+			gopixi.DimensionSet{
+				{Name: "lng", TileSize: tileSize, Size: width},
+				{Name: "lat", TileSize: tileSize, Size: width / 2}},
+			gopixi.ChannelSet{
+				{Name: "ice-thickness", Type: gopixi.ChannelInt16},
+				{Name: "ice-class", Type: gopixi.ChannelUint8}},
+			gopixi.WithCompression(gopixi.CompressionFlate)),
+		sample: func(coord gopixi.SampleCoordinate) gopixi.Sample {
+			ice := value(coord[0], coord[1]).IceGeometry()
+			return gopixi.Sample{int16(ice.Thickness), uint8(ice.Class)}
+		},
+	}
+}
+
+func TestDatasetIceGrid(t *testing.T) {
+	// open water in the north, bare land in the east, and an ice sheet grounded on land running into a shelf in the
+	// south
+	value := func(x, y int) GebcoSample {
+		switch {
+		case y < 9:
+			return GebcoSample{Ice: -1000, SubIce: -1000, Tid: GebcoTypeSingleBeam}
+		case x >= 18:
+			return GebcoSample{Ice: 300, SubIce: 300, Tid: GebcoTypeLand}
+		case x < 9:
+			return GebcoSample{Ice: int16(1000 + 100*y), SubIce: 200, Tid: GebcoTypeLand}
+		}
+		return GebcoSample{Ice: 50, SubIce: int16(-100 * y)}
+	}
+	dataset := writeTestDataset(t, 36, 6, value, iceTestLayer(36, 6, value))
+	if dataset.Grid().Name() != "gebco" || len(dataset.Grids()) != 1 {
+		t.Fatalf("expected the gebco layer as the only grid, got %d grids", len(dataset.Grids()))
+	}
+	iceGrid, err := dataset.IceGrid()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if iceGrid.Width() != 36 || iceGrid.Height() != 18 {
+		t.Fatalf("unexpected ice grid shape %dx%d", iceGrid.Width(), iceGrid.Height())
+	}
+
+	classes := map[IceClass]int{}
+	for y := range iceGrid.Height() {
+		for x := range iceGrid.Width() {
+			position := dataset.Grid().PixelCenter(x, y)
+			ice, err := iceGrid.SampleAt(LatLng{Lat: position.Lat, Lng: position.Lng + 360})
+			if err != nil {
+				t.Fatal(err)
+			}
+			sample, err := dataset.Grid().Sample(x, y)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := sample.IceGeometry(); ice != expected {
+				t.Fatalf("pixel (%d,%d): expected %+v, got %+v", x, y, expected, ice)
+			}
+			classes[ice.Class]++
+		}
+	}
+	if len(classes) != 4 {
+		t.Errorf("expected every ice class, got %v", classes)
+	}
+
+	if _, err := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample { return GebcoSample{} }).IceGrid(); err == nil {
+		t.Errorf("expected an error for a dataset without an ice layer")
+	}
+}
@@ -0,0 +1,111 @@
+package gebco
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gracefulearth/gopixi"
+)
+
+// Interpolation selects how values between pixel centres are estimated.
+type Interpolation int
+
+const (
+	InterpolateNearest  Interpolation = iota // The value of the pixel containing the position.
+	InterpolateBilinear                      // Linear interpolation between the four surrounding pixel centres.
+	InterpolateBicubic                       // Catmull-Rom cubic interpolation over the sixteen surrounding pixel centres.
+)
+
+func (i Interpolation) MarshalText() ([]byte, error) {
+	switch i {
+	case InterpolateNearest:
+		return []byte("nearest"), nil
+	case InterpolateBilinear:
+		return []byte("bilinear"), nil
+	case InterpolateBicubic:
+		return []byte("bicubic"), nil
+	default:
+		return nil, fmt.Errorf("unknown Interpolation %d", i)
+	}
+}
+
+// UnmarshalText parses an interpolation mode by name (nearest, bilinear or bicubic).
+func (i *Interpolation) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "nearest":
+		*i = InterpolateNearest
+	case "bilinear":
+		*i = InterpolateBilinear
+	case "bicubic":
+		*i = InterpolateBicubic
+	default:
+		return fmt.Errorf("unknown interpolation %q (expected nearest, bilinear or bicubic)", text)
+	}
+	return nil
+}
+
+// Interpolate estimates the value of a channel at a position. Type IDs are categorical and always use the
+// nearest pixel. Neighbourhoods wrap around the antimeridian and over the poles.
+func (g *Grid) Interpolate(p LatLng, data GebcoDataType, mode Interpolation) (float64, error) {
+	return g.interpolateInto(p, data, mode, g.newSampleBuffer())
+}
+
+// interpolateInto is Interpolate reading the pixels through a buffer made by newSampleBuffer.
+func (g *Grid) interpolateInto(p LatLng, data GebcoDataType, mode Interpolation, buf gopixi.Sample) (float64, error) {
+	if mode == InterpolateNearest || data == GebcoDataTypeId {
+		x, y := g.Pixel(p)
+		sample, err := g.sampleInto(x, y, buf)
+		if err != nil {
+			return 0, err
+		}
+		return sample.Value(data), nil
+	}
+
+	// continuous coordinates relative to pixel centres
+	fx, fy := g.pixelCoordinate(p)
+	fx, fy = fx-0.5, fy-0.5
+	x0, y0 := int(math.Floor(fx)), int(math.Floor(fy))
+	tx, ty := fx-float64(x0), fy-float64(y0)
+
+	value := func(x, y int) (float64, error) {
+		x, y = g.acrossPole(x, y)
+		sample, err := g.sampleInto(x, y, buf)
+		if err != nil {
+			return 0, err
+		}
+		return sample.Value(data), nil
+	}
+
+	if mode == InterpolateBilinear {
+		var corners [4]float64
+		for i := range corners {
+			v, err := value(x0+i%2, y0+i/2)
+			if err != nil {
+				return 0, err
+			}
+			corners[i] = v
+		}
+		top := corners[0] + (corners[1]-corners[0])*tx
+		bottom := corners[2] + (corners[3]-corners[2])*tx
+		return top + (bottom-top)*ty, nil
+	}
+
+	var rows [4]float64
+	for j := range 4 {
+		var row [4]float64
+		for i := range 4 {
+			v, err := value(x0-1+i, y0-1+j)
+			if err != nil {
+				return 0, err
+			}
+			row[i] = v
+		}
+		rows[j] = catmullRom(row, tx)
+	}
+	return catmullRom(rows, ty), nil
+}
+
+// catmullRom interpolates between p[1] and p[2] at t in [0, 1].
+func catmullRom(p [4]float64, t float64) float64 {
+	return p[1] + 0.5*t*(p[2]-p[0]+t*(2*p[0]-5*p[1]+4*p[2]-p[3]+t*(3*(p[1]-p[2])+p[3]-p[0])))
+}
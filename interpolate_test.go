@@ -0,0 +1,39 @@
+package gebco
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGridInterpolate(t *testing.T) {
+	// a linear ramp in x, so bilinear and bicubic interpolation are exact between pixel centres
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		return GebcoSample{Ice: int16(10 * x), SubIce: int16(10 * x), Tid: GebcoTypeId(x)}
+	})
+	grid := dataset.Grid()
+
+	cases := []struct {
+		name     string
+		position LatLng
+		data     GebcoDataType
+		mode     Interpolation
+		expected float64
+	}{
+		{"nearest", LatLng{Lat: 0, Lng: -171}, GebcoDataIce, InterpolateNearest, 0},
+		{"bilinear midway", LatLng{Lat: 0, Lng: -170}, GebcoDataIce, InterpolateBilinear, 5},
+		{"bilinear quarter", LatLng{Lat: 12, Lng: -167.5}, GebcoDataIce, InterpolateBilinear, 7.5},
+		{"bicubic midway", LatLng{Lat: 0, Lng: -150}, GebcoDataIce, InterpolateBicubic, 25},
+		{"tid is always nearest", LatLng{Lat: 0, Lng: -171}, GebcoDataTypeId, InterpolateBilinear, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := grid.Interpolate(c.position, c.data, c.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(actual-c.expected) > 1e-9 {
+				t.Errorf("expected %f, got %f", c.expected, actual)
+			}
+		})
+	}
+}
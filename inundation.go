@@ -0,0 +1,164 @@
+package gebco
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// InundationOptions configures Inundate.
+type InundationOptions struct {
+	Region   Bounds  // The region to flood, expanded to whole pixels of the grid.
+	SeaLevel float64 // The rise in sea level in metres.
+	CellSize float64 // The size in degrees of the cells that the flooded area is summarised over.
+	MinArea  float64 // The area in square metres below which flooded polygons are dropped.
+	// OceanSeeds are positions in the ocean the flood also starts from, for oceans that do not reach the edges of
+	// the region.
+	OceanSeeds []LatLng
+}
+
+// InundationCell summarises the land flooded within a cell of the region.
+type InundationCell struct {
+	Bounds        Bounds  `json:"bounds"`
+	LandPixels    int     `json:"landPixels"`
+	LandArea      float64 `json:"landArea"` // The area of land before the rise in square metres.
+	FloodedPixels int     `json:"floodedPixels"`
+	FloodedArea   float64 `json:"floodedArea"` // The area of newly flooded land in square metres.
+}
+
+// Inundation is the land a region loses to a rise in sea level.
+type Inundation struct {
+	// Raster is the region in plate carrée with a "flooded" band that is 1 on newly flooded land, 0 on land that
+	// stays dry and NaN in the sea.
+	Raster   *Raster          `json:"-"`
+	Total    InundationCell   `json:"total"`
+	Cells    []InundationCell `json:"cells"`    // Ordered from north to south, then west to east, omitting cells without land.
+	Polygons []Polygon        `json:"polygons"` // The flooded areas, largest first.
+}
+
+// Inundate floods a region from the sea after a rise in sea level. The flood starts from the ocean, the water on the
+// edges of the region (the poles only for a region spanning the globe) and at the ocean seeds, and spreads across
+// the edges of pixels through water and through land whose ice surface lies below the new sea level. Depressions
+// behind higher ground, and the land around lakes and inland seas that the ocean does not reach, stay dry rather
+// than being flooded by a simple threshold; water crossing the edges of the region is taken to be ocean, so they
+// should lie in the ocean. A region spanning the globe wraps across the antimeridian.
+func Inundate(grid *Grid, opts InundationOptions) (*Inundation, error) {
+	if opts.SeaLevel <= 0 {
+		return nil, fmt.Errorf("invalid sea level rise %g", opts.SeaLevel)
+	}
+	if opts.CellSize <= 0 {
+		return nil, fmt.Errorf("invalid cell size %g", opts.CellSize)
+	}
+	region, err := grid.Region(opts.Region)
+	if err != nil {
+		return nil, err
+	}
+	width, height := region.Width, region.Height
+	samples, err := region.ReadSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	// reached marks the ocean and the land it floods
+	reached := make([]bool, len(samples))
+	stack := []int{}
+	seed := func(i, j int) {
+		if pixel := region.Index(i, j); !samples[pixel].IsLand() && !reached[pixel] {
+			reached[pixel] = true
+			stack = append(stack, pixel)
+		}
+	}
+	for i := range width {
+		seed(i, 0)
+		seed(i, height-1)
+	}
+	if !region.Wraps() {
+		for j := range height {
+			seed(0, j)
+			seed(width-1, j)
+		}
+	}
+	for _, p := range opts.OceanSeeds {
+		x, y := grid.Pixel(p)
+		if i, j := (x-region.X+grid.width)%grid.width, y-region.Y; i < width && j >= 0 && j < height {
+			seed(i, j)
+		}
+	}
+	for len(stack) > 0 {
+		pixel := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		i, j := pixel%width, pixel/width
+		for _, n := range [4][2]int{{i - 1, j}, {i + 1, j}, {i, j - 1}, {i, j + 1}} {
+			ni, nj := n[0], n[1]
+			if nj < 0 || nj >= height {
+				continue
+			}
+			if ni < 0 || ni >= width {
+				if !region.Wraps() {
+					continue
+				}
+				ni = (ni + width) % width
+			}
+			neighbour := region.Index(ni, nj)
+			if sample := samples[neighbour]; !reached[neighbour] && (!sample.IsLand() || float64(sample.Ice) < opts.SeaLevel) {
+				reached[neighbour] = true
+				stack = append(stack, neighbour)
+			}
+		}
+	}
+	flooded := make([]bool, len(samples))
+	for pixel, sample := range samples {
+		flooded[pixel] = reached[pixel] && sample.IsLand()
+	}
+
+	step := grid.DegreesPerPixel()
+	raster := NewRaster(PlateCarree{}, region.West(), region.North(), step, step, width, height, "flooded")
+	values := make([]float32, len(samples))
+	total := InundationCell{Bounds: opts.Region}
+	cells := map[[2]int]*InundationCell{}
+	for pixel, sample := range samples {
+		// values above zero inside the flooded land place its outline at the new sea level
+		values[pixel] = float32(opts.SeaLevel) - float32(sample.Ice)
+		if !sample.IsLand() {
+			continue
+		}
+
+		center := grid.PixelCenter(grid.wrapX(region.X+pixel%width), region.Y+pixel/width)
+		key, bounds := summaryCell(center, opts.CellSize)
+		cell, ok := cells[key]
+		if !ok {
+			cell = &InundationCell{Bounds: bounds}
+			cells[key] = cell
+		}
+		area := CellArea(center.Lat, step)
+		for _, c := range []*InundationCell{cell, &total} {
+			c.LandPixels++
+			c.LandArea += area
+			if flooded[pixel] {
+				c.FloodedPixels++
+				c.FloodedArea += area
+			}
+		}
+		raster.Bands[0][pixel] = 0
+		if flooded[pixel] {
+			raster.Bands[0][pixel] = 1
+		}
+	}
+
+	inundation := &Inundation{Raster: raster, Total: total, Cells: []InundationCell{}, Polygons: []Polygon{}}
+	for _, cell := range cells {
+		inundation.Cells = append(inundation.Cells, *cell)
+	}
+	slices.SortFunc(inundation.Cells, func(a, b InundationCell) int {
+		return cmp.Or(cmp.Compare(b.Bounds.North, a.Bounds.North), cmp.Compare(a.Bounds.West, b.Bounds.West))
+	})
+	for _, polygon := range polygonise(region, flooded, values) {
+		if polygon.Area >= opts.MinArea {
+			inundation.Polygons = append(inundation.Polygons, polygon)
+		}
+	}
+	slices.SortStableFunc(inundation.Polygons, func(a, b Polygon) int {
+		return cmp.Compare(b.Area, a.Area)
+	})
+	return inundation, nil
+}
@@ -0,0 +1,109 @@
+package gebco
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInundate(t *testing.T) {
+	// a 7x7 pixel island of 10 degree pixels with a low coast, a ring of hills and a depression below sea level
+	// inside them
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		switch {
+		case x >= 12 && x < 15 && y >= 5 && y < 8:
+			return GebcoSample{Ice: -2, SubIce: -2, Tid: GebcoTypeLand}
+		case x >= 11 && x < 16 && y >= 4 && y < 9:
+			return GebcoSample{Ice: 100, SubIce: 100, Tid: GebcoTypeLand}
+		case x >= 10 && x < 17 && y >= 3 && y < 10:
+			return GebcoSample{Ice: 2, SubIce: 2, Tid: GebcoTypeLand}
+		}
+		return GebcoSample{Ice: -1000, SubIce: -1000, Tid: GebcoTypeSingleBeam}
+	})
+
+	inundation, err := Inundate(dataset.Grid(), InundationOptions{Region: GlobalBounds, SeaLevel: 5, CellSize: 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inundation.Total.LandPixels != 49 || inundation.Total.FloodedPixels != 24 {
+		t.Errorf("expected 24 of 49 land pixels flooded, got %d of %d", inundation.Total.FloodedPixels, inundation.Total.LandPixels)
+	}
+	if inundation.Total.FloodedArea <= 0 || inundation.Total.FloodedArea >= inundation.Total.LandArea {
+		t.Errorf("expected part of the land area %f flooded, got %f", inundation.Total.LandArea, inundation.Total.FloodedArea)
+	}
+	if len(inundation.Cells) != 2 || inundation.Cells[0].Bounds.North != 90 || inundation.Cells[1].Bounds.North != 0 {
+		t.Errorf("expected the island summarised in the cells north and south of the equator, got %+v", inundation.Cells)
+	}
+
+	testCases := []struct {
+		name     string
+		x, y     int
+		expected float64
+	}{
+		{"coast", 10, 3, 1},
+		{"hills", 11, 4, 0},
+		{"depression", 13, 6, 0},
+		{"sea", 20, 6, math.NaN()},
+	}
+	for _, tc := range testCases {
+		actual := float64(inundation.Raster.Bands[0][tc.y*36+tc.x])
+		if actual != tc.expected && !(math.IsNaN(actual) && math.IsNaN(tc.expected)) {
+			t.Errorf("%s: expected %f, got %f", tc.name, tc.expected, actual)
+		}
+	}
+
+	if len(inundation.Polygons) != 1 || len(inundation.Polygons[0].Holes) != 1 {
+		t.Fatalf("expected the flooded coast as a ring around the hills, got %+v", inundation.Polygons)
+	}
+
+	// a higher sea overtops the hills and floods the depression too
+	inundation, err = Inundate(dataset.Grid(), InundationOptions{Region: GlobalBounds, SeaLevel: 200, CellSize: 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inundation.Total.FloodedPixels != 49 {
+		t.Errorf("expected the whole island flooded, got %d pixels", inundation.Total.FloodedPixels)
+	}
+}
+
+func TestInundateInlandWater(t *testing.T) {
+	// a 5x5 pixel island of 10 degree pixels with a ring of hills around a lake and low land beside it
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		switch {
+		case x == 12 && y == 6:
+			return GebcoSample{Ice: -50, SubIce: -50, Tid: GebcoTypeSingleBeam}
+		case x >= 11 && x < 14 && y >= 5 && y < 8:
+			return GebcoSample{Ice: 1, SubIce: 1, Tid: GebcoTypeLand}
+		case x >= 10 && x < 15 && y >= 4 && y < 9:
+			return GebcoSample{Ice: 100, SubIce: 100, Tid: GebcoTypeLand}
+		}
+		return GebcoSample{Ice: -1000, SubIce: -1000, Tid: GebcoTypeSingleBeam}
+	})
+
+	inundation, err := Inundate(dataset.Grid(), InundationOptions{Region: GlobalBounds, SeaLevel: 5, CellSize: 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inundation.Total.FloodedPixels != 0 {
+		t.Errorf("expected the low land around the lake to stay dry, got %d pixels flooded", inundation.Total.FloodedPixels)
+	}
+	if lake := inundation.Raster.Bands[0][6*36+12]; !math.IsNaN(float64(lake)) {
+		t.Errorf("expected the lake left out of the land, got %f", lake)
+	}
+
+	// a region with its edges on the island only floods from the ocean seeds
+	region := Bounds{West: -80, South: 0, East: -30, North: 40}
+	inundation, err = Inundate(dataset.Grid(), InundationOptions{Region: region, SeaLevel: 5, CellSize: 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inundation.Total.FloodedPixels != 0 {
+		t.Errorf("expected no ocean to flood from, got %d pixels flooded", inundation.Total.FloodedPixels)
+	}
+	inundation, err = Inundate(dataset.Grid(), InundationOptions{Region: region, SeaLevel: 5, CellSize: 90, OceanSeeds: []LatLng{{Lat: 25, Lng: -55}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inundation.Total.FloodedPixels != 8 {
+		t.Errorf("expected the low land flooded from the lake as a seed, got %d pixels flooded", inundation.Total.FloodedPixels)
+	}
+}
@@ -19,7 +19,9 @@ type tileWriteCommand struct {
 // GebcoTileOrderWriteIterator implements gopixi.IterativeLayerWriter writing tiles in GEBCO tiff tile order.
 // This is so we only have to load one GEBCO tile at a time when building from GEBCO tiff files. This particular
 // iterator requires the Pixi layer to have a tile size that is a divisor of the GEBCO tile size (21600x21600). It
-// also assumes the layer dimensions are ordered x then y (i.e. row-major order).
+// also assumes the layer dimensions are ordered x then y (i.e. row-major order). Any further dimensions (e.g. the
+// year of a stack of GEBCO releases) are iterated slowest, with one pass over the GEBCO tiles for each of their
+// tiles, so with a tile size of one along them each pass reads from a single set of GEBCO tiff files.
 type GebcoTileOrderWriteIterator struct {
 	backing                      io.WriteSeeker
 	header                       gopixi.Header
@@ -29,7 +31,7 @@ type GebcoTileOrderWriteIterator struct {
 
 	sampleInPixiTile int // the index of the current sample within the current Pixi tile
 	pixiTileInGebco  int // the index of this Pixi tile within the current GEBCO tile
-	gebcoTile        int // the index of the current 21600x21600 GEBCO tile being read from
+	gebcoTile        int // the index of the current 21600x21600 GEBCO tile being read from, counting across passes
 
 	wg           sync.WaitGroup
 	writeLock    sync.RWMutex
@@ -99,15 +101,17 @@ func (t *GebcoTileOrderWriteIterator) Error() error {
 }
 
 func (t *GebcoTileOrderWriteIterator) tile() int {
-	xGebco := t.gebcoTile % TilesX
-	yGebco := t.gebcoTile / TilesX
+	pass := t.gebcoTile / Tiles
+	xGebco := (t.gebcoTile % Tiles) % TilesX
+	yGebco := (t.gebcoTile % Tiles) / TilesX
 
 	yInGebco := t.pixiTileInGebco / t.pixiTilesPerGebcoTilePerAxis
 	xInGebco := t.pixiTileInGebco % t.pixiTilesPerGebcoTilePerAxis
 
 	xTile := xGebco*t.pixiTilesPerGebcoTilePerAxis + xInGebco
 	yTile := yGebco*t.pixiTilesPerGebcoTilePerAxis + yInGebco
-	return yTile*t.layer.Dimensions[0].Tiles() + xTile
+	passTiles := t.layer.Dimensions[0].Tiles() * t.layer.Dimensions[1].Tiles()
+	return pass*passTiles + yTile*t.layer.Dimensions[0].Tiles() + xTile
 }
 
 func (t *GebcoTileOrderWriteIterator) Next() bool {
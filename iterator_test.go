@@ -104,3 +104,40 @@ func TestTileOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestTileOrderYears(t *testing.T) {
+	pixiTileSize := GtiffTileSize / 2
+	iterator := &GebcoTileOrderWriteIterator{
+		layer: gopixi.NewLayer(
+			"testTile",
+			gopixi.DimensionSet{
+				{Name: "lng", TileSize: pixiTileSize, Size: TotalWidth},
+				{Name: "lat", TileSize: pixiTileSize, Size: TotalHeight},
+				{Name: "year", TileSize: 1, Size: 2},
+			},
+			gopixi.ChannelSet{
+				{Name: "sample", Type: gopixi.ChannelUint16},
+			},
+		),
+		sampleInPixiTile:             -1,
+		pixiTilesPerGebcoTilePerAxis: 2,
+		pixiTilesPerGebcoTile:        4,
+	}
+
+	// the second year repeats the order of the first, offset by the tiles of a whole year
+	var actualTileOrder []int
+	for iterator.gebcoTile = range 2 * Tiles {
+		for iterator.pixiTileInGebco = range iterator.pixiTilesPerGebcoTile {
+			actualTileOrder = append(actualTileOrder, iterator.tile())
+		}
+	}
+	expectedTileOrder := []int{
+		0, 1, 8, 9, 2, 3, 10, 11, 4, 5, 12, 13, 6, 7, 14, 15,
+		16, 17, 24, 25, 18, 19, 26, 27, 20, 21, 28, 29, 22, 23, 30, 31,
+		32, 33, 40, 41, 34, 35, 42, 43, 36, 37, 44, 45, 38, 39, 46, 47,
+		48, 49, 56, 57, 50, 51, 58, 59, 52, 53, 60, 61, 54, 55, 62, 63,
+	}
+	if !slices.Equal(actualTileOrder, expectedTileOrder) {
+		t.Errorf("tile order mismatch: got %v, want %v", actualTileOrder, expectedTileOrder)
+	}
+}
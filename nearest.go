@@ -0,0 +1,128 @@
+package gebco
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// NearestOptions configures Nearest.
+type NearestOptions struct {
+	MaxRadius float64                // The greatest distance in metres to search from the position.
+	Match     func(GebcoSample) bool // The criterion a pixel must satisfy, e.g. a depth below 1000 m or land.
+	MaxPixels int                    // The number of pixels to visit before giving up, or 0 for no limit.
+}
+
+// NearestStatus tells how a search of Nearest ended.
+type NearestStatus uint8
+
+const (
+	NearestNone      NearestStatus = iota // No pixel within the maximum radius matches.
+	NearestFound                          // The match is the nearest matching pixel.
+	NearestTruncated                      // The search gave up after visiting MaxPixels pixels without settling on a match.
+)
+
+// MarshalText returns the name of the status.
+func (s NearestStatus) MarshalText() ([]byte, error) {
+	switch s {
+	case NearestNone:
+		return []byte("none"), nil
+	case NearestFound:
+		return []byte("found"), nil
+	case NearestTruncated:
+		return []byte("truncated"), nil
+	default:
+		return nil, fmt.Errorf("unknown NearestStatus %d", s)
+	}
+}
+
+// String returns the name of the status.
+func (s NearestStatus) String() string {
+	name, err := s.MarshalText()
+	if err != nil {
+		return err.Error()
+	}
+	return string(name)
+}
+
+// NearestMatch is the pixel nearest to a position satisfying a criterion.
+type NearestMatch struct {
+	Position    LatLng  `json:"position"` // The centre of the matching pixel.
+	Distance    float64 `json:"distance"` // The great-circle distance in metres from the position to the centre.
+	Bearing     float64 `json:"bearing"`  // The initial bearing in degrees clockwise from north to the centre.
+	GebcoSample         // The values of the matching pixel.
+}
+
+// Nearest searches outward from a position for the nearest pixel of a grid satisfying a criterion, returning
+// NearestNone when none lies within the maximum radius, and NearestTruncated without a match when it visits more
+// than MaxPixels pixels before settling on one, as a match found by then may not be the nearest. Pixels are
+// visited from the one containing the position in order of the great-circle distance to their centres, spreading
+// to their neighbours across the antimeridian and around the poles, so that only the pixels nearer than the match
+// are read. As the visiting order can run up to a pixel out near the poles, the search goes on a pixel diagonal
+// beyond the first match before settling on the nearest.
+func Nearest(grid *Grid, from LatLng, opts NearestOptions) (NearestMatch, NearestStatus, error) {
+	if opts.MaxRadius <= 0 {
+		return NearestMatch{}, NearestNone, fmt.Errorf("invalid maximum radius %g", opts.MaxRadius)
+	}
+	if opts.Match == nil {
+		return NearestMatch{}, NearestNone, fmt.Errorf("no criterion to match")
+	}
+	index := func(x, y int) int {
+		return y*grid.width + x
+	}
+	// the widest pixels straddle the equator
+	diagonal := math.Sqrt2 * grid.PixelSpacing()
+
+	startX, startY := grid.Pixel(from)
+	start := index(startX, startY)
+	seen := map[int]bool{start: true}
+	queue := &routeQueue{{pixel: start, estimate: Distance(from, grid.PixelCenter(startX, startY))}}
+	best, found := NearestMatch{}, false
+	visited := 0
+	buf := grid.newSampleBuffer()
+	for queue.Len() > 0 {
+		node := heap.Pop(queue).(routeNode)
+		if found && node.estimate > best.Distance+diagonal {
+			break
+		}
+		visited++
+		if opts.MaxPixels > 0 && visited > opts.MaxPixels {
+			return NearestMatch{}, NearestTruncated, nil
+		}
+
+		x, y := node.pixel%grid.width, node.pixel/grid.width
+		if node.estimate <= opts.MaxRadius && (!found || node.estimate < best.Distance) {
+			sample, err := grid.sampleInto(x, y, buf)
+			if err != nil {
+				return NearestMatch{}, NearestNone, err
+			}
+			if opts.Match(sample) {
+				center := grid.PixelCenter(x, y)
+				best = NearestMatch{Position: center, Distance: node.estimate, Bearing: InitialBearing(from, center), GebcoSample: sample}
+				found = true
+			}
+		}
+
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if dx == 0 && dy == 0 {
+					continue
+				}
+				nx, ny := grid.acrossPole(x+dx, y+dy)
+				neighbour := index(nx, ny)
+				if seen[neighbour] {
+					continue
+				}
+				seen[neighbour] = true
+				// pixels just beyond the radius are still visited so that the pixels within it they lead to are reached
+				if distance := Distance(from, grid.PixelCenter(nx, ny)); distance <= opts.MaxRadius+diagonal {
+					heap.Push(queue, routeNode{pixel: neighbour, estimate: distance})
+				}
+			}
+		}
+	}
+	if !found {
+		return NearestMatch{}, NearestNone, nil
+	}
+	return best, NearestFound, nil
+}
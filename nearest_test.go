@@ -0,0 +1,76 @@
+package gebco
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNearest(t *testing.T) {
+	// a seafloor of 10 degree pixels at 1000 m with deep pixels east of the prime meridian, across the antimeridian
+	// and by the north pole, and an island
+	deep := map[[2]int]bool{{21, 8}: true, {18, 12}: true, {1, 8}: true, {0, 0}: true}
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		switch {
+		case deep[[2]int{x, y}]:
+			return GebcoSample{Ice: -5000, SubIce: -5000, Tid: GebcoTypeMultiBeam}
+		case x == 10 && y == 10:
+			return GebcoSample{Ice: 200, SubIce: 200, Tid: GebcoTypeLand}
+		}
+		return GebcoSample{Ice: -1000, SubIce: -1000, Tid: GebcoTypeInterpolated}
+	})
+	grid := dataset.Grid()
+	deeper := func(sample GebcoSample) bool {
+		return sample.SubIce < -3000
+	}
+
+	cases := []struct {
+		name     string
+		from     LatLng
+		match    func(GebcoSample) bool
+		expected LatLng
+	}{
+		{"east before south", LatLng{Lat: 5, Lng: 5}, deeper, LatLng{Lat: 5, Lng: 35}},
+		{"across the antimeridian", LatLng{Lat: 5, Lng: 175}, deeper, LatLng{Lat: 5, Lng: -165}},
+		{"over the pole", LatLng{Lat: 85, Lng: 5}, deeper, LatLng{Lat: 85, Lng: -175}},
+		{"land", LatLng{Lat: 0, Lng: 0}, GebcoSample.IsLand, LatLng{Lat: -15, Lng: -75}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			match, status, err := Nearest(grid, c.from, NearestOptions{MaxRadius: 10000e3, Match: c.match})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status != NearestFound || match.Position != c.expected {
+				t.Fatalf("expected a match at %v, got %+v (%v)", c.expected, match, status)
+			}
+			if math.Abs(match.Distance-Distance(c.from, c.expected)) > 1e-6 || !c.match(match.GebcoSample) {
+				t.Errorf("unexpected match %+v", match)
+			}
+		})
+	}
+
+	// a radius short of the nearest match finds nothing
+	_, status, err := Nearest(grid, LatLng{Lat: 5, Lng: 5}, NearestOptions{MaxRadius: 3000e3, Match: deeper})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != NearestNone {
+		t.Errorf("expected no match within 3000 km, got %v", status)
+	}
+
+	// too few pixels to reach the match gives up without one, while enough to settle on it finds it
+	match, status, err := Nearest(grid, LatLng{Lat: 5, Lng: 5}, NearestOptions{MaxRadius: 10000e3, Match: deeper, MaxPixels: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != NearestTruncated || match != (NearestMatch{}) {
+		t.Errorf("expected the search to give up without a match, got %+v (%v)", match, status)
+	}
+	match, status, err = Nearest(grid, LatLng{Lat: 5, Lng: 5}, NearestOptions{MaxRadius: 10000e3, Match: deeper, MaxPixels: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != NearestFound || match.Position != (LatLng{Lat: 5, Lng: 35}) {
+		t.Errorf("expected a match within 1000 pixels, got %+v (%v)", match, status)
+	}
+}
@@ -0,0 +1,205 @@
+package gebco
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// PeakOptions configures Peaks.
+type PeakOptions struct {
+	Region    Bounds  // The region to search, expanded to whole pixels of the grid.
+	MinHeight float64 // The height in metres of a summit above its base below which peaks are dropped.
+	MinArea   float64 // The area in square metres of the footprint below which peaks are dropped.
+	Submarine bool    // Whether to keep only peaks whose summits lie below sea level, such as seamounts and knolls.
+}
+
+// Peak is a local maximum of the sub-ice surface. Its base is the key col, the highest level at which it joins
+// ground rising to a higher summit, so its height above the base is its topographic prominence, and its footprint
+// is the ground above the base that joins its summit.
+type Peak struct {
+	Summit      LatLng  `json:"summit"`      // The centre of the highest pixel of the peak.
+	SummitDepth float64 `json:"summitDepth"` // The depth in metres of the summit, negative above sea level.
+	BaseDepth   float64 `json:"baseDepth"`   // The depth in metres of the base, negative above sea level.
+	Height      float64 `json:"height"`      // The height in metres of the summit above its base.
+	Pixels      int     `json:"pixels"`
+	Area        float64 `json:"area"` // The area in square metres of the footprint.
+}
+
+// Feature returns the peak as a GeoJSON Point feature at its summit, with its depths, height and footprint area
+// in square kilometres as properties.
+func (p Peak) Feature() GeoJsonFeature {
+	return NewGeoJsonFeature(PointGeometry(p.Summit), map[string]any{
+		"summitDepth": p.SummitDepth,
+		"baseDepth":   p.BaseDepth,
+		"height":      p.Height,
+		"pixels":      p.Pixels,
+		"areaKm2":     p.Area / 1e6,
+	})
+}
+
+// Peaks finds the peaks of the sub-ice surface of a region that rise at least the minimum height above their
+// base over at least the minimum footprint, highest first. Pixels are flooded from the top down, joining pixels
+// that share an edge or a corner and continuing across the antimeridian in a region spanning the globe; a peak
+// ends at the level where its ground first meets that of a higher summit. The highest summit of the region never
+// meets a higher one, so its base is the lowest ground of the region. Peaks cut by the edge of a region that does
+// not span the globe may be lower than they appear.
+func Peaks(grid *Grid, opts PeakOptions) ([]Peak, error) {
+	if opts.MinHeight < 0 {
+		return nil, fmt.Errorf("invalid minimum peak height %g", opts.MinHeight)
+	}
+	region, err := grid.Region(opts.Region)
+	if err != nil {
+		return nil, err
+	}
+	elevation, err := region.ReadValues(GebcoDataSubIce)
+	if err != nil {
+		return nil, err
+	}
+
+	peaks := []Peak{}
+	for _, p := range prominences(region, elevation, nil) {
+		peak := Peak{
+			Summit:      grid.PixelCenter(grid.wrapX(region.X+p.summit%region.Width), region.Y+p.summit/region.Width),
+			SummitDepth: -float64(elevation[p.summit]),
+			BaseDepth:   -float64(p.base),
+			Height:      float64(elevation[p.summit] - p.base),
+			Pixels:      p.pixels,
+			Area:        p.area,
+		}
+		if peak.Height >= opts.MinHeight && peak.Area >= opts.MinArea && (!opts.Submarine || peak.SummitDepth > 0) {
+			peaks = append(peaks, peak)
+		}
+	}
+	slices.SortStableFunc(peaks, func(a, b Peak) int {
+		return cmp.Or(cmp.Compare(b.Height, a.Height), cmp.Compare(b.Summit.Lat, a.Summit.Lat), cmp.Compare(a.Summit.Lng, b.Summit.Lng))
+	})
+	return peaks, nil
+}
+
+// prominence is a local maximum of the values of a region with its base, the highest level at which it joins
+// ground rising to a higher maximum, and its footprint above that level.
+type prominence struct {
+	summit  int     // The index of the pixel of the maximum in the region.
+	base    float32 // The level of the key col, or the lowest value of its piece of the domain for the highest maximum.
+	pixels  int
+	area    float64
+	highest bool // Whether it is the highest maximum of its piece of the domain.
+}
+
+// prominences floods the values of the pixels of a region within a domain, or of every pixel if the domain is nil,
+// from the top down, joining pixels that share an edge or a corner and continuing across the antimeridian in a
+// region that wraps, and returns every local maximum in the order of its pixels. The highest maximum of each
+// connected piece of the domain never meets a higher one, so its base is the lowest value of the piece.
+func prominences(region GridRegion, values []float32, domain []bool) []prominence {
+	width, height := region.Width, region.Height
+	order := []int32{}
+	for pixel := range values {
+		if domain == nil || domain[pixel] {
+			order = append(order, int32(pixel))
+		}
+	}
+	slices.SortStableFunc(order, func(a, b int32) int {
+		return cmp.Compare(values[b], values[a])
+	})
+
+	// the pixels flooded so far form components, each rooted at its summit with the footprint it has gathered above
+	// the level being flooded, and the pixels it has gathered at that level, which join its footprint only once the
+	// flood goes lower
+	type footprint struct {
+		pixels int
+		area   float64
+	}
+	type component struct {
+		level          float32
+		above, atLevel footprint
+	}
+	lower := func(c *component, level float32) {
+		if level < c.level {
+			c.above.pixels += c.atLevel.pixels
+			c.above.area += c.atLevel.area
+			c.atLevel, c.level = footprint{}, level
+		}
+	}
+	parent := make([]int32, len(values))
+	for pixel := range parent {
+		parent[pixel] = -1
+	}
+	root := func(pixel int32) int32 {
+		for parent[pixel] != pixel {
+			parent[pixel] = parent[parent[pixel]]
+			pixel = parent[pixel]
+		}
+		return pixel
+	}
+	components := map[int32]*component{}
+	step := region.Grid.DegreesPerPixel()
+	result := []prominence{}
+	end := func(summit int32, base float32, c *component, highest bool) {
+		lower(c, base)
+		result = append(result, prominence{summit: int(summit), base: base, pixels: c.above.pixels, area: c.above.area, highest: highest})
+	}
+
+	roots := []int32{}
+	for _, pixel := range order {
+		i, j := int(pixel)%width, int(pixel)/width
+		roots = roots[:0]
+		for nj := j - 1; nj <= j+1; nj++ {
+			for ni := i - 1; ni <= i+1; ni++ {
+				if nj < 0 || nj >= height || (ni == i && nj == j) {
+					continue
+				}
+				wrapped := ni
+				if ni < 0 || ni >= width {
+					if !region.Wraps() {
+						continue
+					}
+					wrapped = (ni + width) % width
+				}
+				if neighbour := int32(region.Index(wrapped, nj)); parent[neighbour] >= 0 {
+					if r := root(neighbour); !slices.Contains(roots, r) {
+						roots = append(roots, r)
+					}
+				}
+			}
+		}
+
+		// a pixel touching no flooded ground is a new summit; one touching several is the col of all but the
+		// highest of their summits, which ends their peaks
+		if len(roots) == 0 {
+			parent[pixel] = pixel
+			components[pixel] = &component{level: values[pixel]}
+			roots = append(roots, pixel)
+		}
+		highest := roots[0]
+		for _, r := range roots[1:] {
+			if values[r] > values[highest] {
+				highest = r
+			}
+		}
+		survivor := components[highest]
+		lower(survivor, values[pixel])
+		for _, r := range roots {
+			if r == highest {
+				continue
+			}
+			end(r, values[pixel], components[r], false)
+			// the ground of the ended peak joins the survivor only at this level
+			survivor.atLevel.pixels += components[r].above.pixels + components[r].atLevel.pixels
+			survivor.atLevel.area += components[r].above.area + components[r].atLevel.area
+			parent[r] = highest
+			delete(components, r)
+		}
+		parent[pixel] = highest
+		survivor.atLevel.pixels++
+		survivor.atLevel.area += CellArea(region.Grid.PixelCenter(0, region.Y+j).Lat, step)
+	}
+	// the level of every remaining component is the lowest value it reached
+	for summit, c := range components {
+		end(summit, c.level, c, true)
+	}
+	slices.SortFunc(result, func(a, b prominence) int {
+		return cmp.Compare(a.summit, b.summit)
+	})
+	return result
+}
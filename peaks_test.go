@@ -0,0 +1,68 @@
+package gebco
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPeaks(t *testing.T) {
+	// a 3x3 pixel seamount, a low knoll, a guyot whose flat top crosses the antimeridian and an island
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		switch {
+		case x == 11 && y == 9:
+			return GebcoSample{Ice: -1000, SubIce: -1000, Tid: GebcoTypeMultiBeam}
+		case x >= 10 && x < 13 && y >= 8 && y < 11:
+			return GebcoSample{Ice: -3000, SubIce: -3000, Tid: GebcoTypeMultiBeam}
+		case x == 20 && y == 5:
+			return GebcoSample{Ice: -4500, SubIce: -4500, Tid: GebcoTypeMultiBeam}
+		case (x == 35 || x == 0) && y == 14:
+			return GebcoSample{Ice: -2000, SubIce: -2000, Tid: GebcoTypeMultiBeam}
+		case x == 30 && y == 12:
+			return GebcoSample{Ice: 500, SubIce: 500, Tid: GebcoTypeLand}
+		}
+		return GebcoSample{Ice: -5000, SubIce: -5000, Tid: GebcoTypeSatelliteGravity}
+	})
+	grid := dataset.Grid()
+
+	peaks, err := Peaks(grid, PeakOptions{Region: GlobalBounds, MinHeight: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peaks) != 4 {
+		t.Fatalf("expected 4 peaks, got %+v", peaks)
+	}
+	island, seamount, guyot, knoll := peaks[0], peaks[1], peaks[2], peaks[3]
+	if island.SummitDepth != -500 || island.BaseDepth != 5000 || island.Height != 5500 {
+		t.Errorf("unexpected island %+v", island)
+	}
+	if seamount.Summit != (LatLng{Lat: -5, Lng: -65}) || seamount.SummitDepth != 1000 || seamount.Height != 4000 || seamount.Pixels != 9 {
+		t.Errorf("unexpected seamount %+v", seamount)
+	}
+	if guyot.Height != 3000 || guyot.Pixels != 2 || math.Abs(guyot.Summit.Lat+55) > 1e-9 || math.Abs(math.Abs(guyot.Summit.Lng)-175) > 1e-9 {
+		t.Errorf("expected one guyot across the antimeridian, got %+v", guyot)
+	}
+	if knoll.Height != 500 || knoll.Pixels != 1 {
+		t.Errorf("unexpected knoll %+v", knoll)
+	}
+	if feature := seamount.Feature(); feature.Geometry.Type != "Point" || feature.Properties["height"] != 4000.0 {
+		t.Errorf("unexpected seamount feature %+v", feature)
+	}
+
+	// only tall seamounts with a footprint larger than the guyot's
+	peaks, err = Peaks(grid, PeakOptions{Region: GlobalBounds, MinHeight: 1000, MinArea: guyot.Area + 1, Submarine: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peaks) != 1 || peaks[0].Summit != seamount.Summit {
+		t.Errorf("expected only the seamount, got %+v", peaks)
+	}
+
+	// a region west of the antimeridian sees only half of the guyot
+	peaks, err = Peaks(grid, PeakOptions{Region: Bounds{West: 0, South: -90, East: 180, North: 90}, MinHeight: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peaks) != 3 || peaks[0].Height != 5500 || peaks[1].Pixels != 1 || peaks[1].Height != 3000 || peaks[2].Height != 500 {
+		t.Errorf("expected the island, half the guyot and the knoll, got %+v", peaks)
+	}
+}
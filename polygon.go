@@ -0,0 +1,241 @@
+package gebco
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// Polygon is an area of the globe with any areas excluded from it as holes. The exterior runs anticlockwise and
+// the holes clockwise, each repeating its first position last, with unwrapped longitudes.
+type Polygon struct {
+	Exterior []LatLng   `json:"exterior"`
+	Holes    [][]LatLng `json:"holes"`
+	Area     float64    `json:"area"` // The area in square metres, excluding the holes.
+}
+
+// Geometry returns the polygon as a GeoJSON Polygon, or a MultiPolygon cut at the antimeridian if it crosses it.
+func (p Polygon) Geometry() GeoJsonGeometry {
+	return PolygonGeometry(p.Exterior, p.Holes)
+}
+
+// polygonise traces the boundaries of the pixels of a region where inside is true as polygons, with marching
+// squares through values that are forced positive inside and negative outside, so every inside pixel centre lies
+// within a polygon and every outside one without while the values place the boundary between them. Nil values
+// place it halfway. The region is surrounded by outside pixels so that every polygon closes; boundaries running
+// around the globe in a region spanning it are closed through a pole.
+func polygonise(region GridRegion, inside []bool, values []float32) []Polygon {
+	// pad the region with a border outside, except across the antimeridian of a region that wraps, and at a pole
+	// reached by such a region, where the border takes the majority of the polar row
+	padded := region
+	offsetX := 0
+	if !region.Wraps() {
+		padded.X, padded.Width, offsetX = region.X-1, region.Width+2, 1
+	}
+	padded.Y, padded.Height = region.Y-1, region.Height+2
+	field := make([]float32, padded.Width*padded.Height)
+	for i := range field {
+		field[i] = -1
+	}
+	insideCount := make([]int, region.Height)
+	for j := range region.Height {
+		for i := range region.Width {
+			index := region.Index(i, j)
+			value := float32(0)
+			if values != nil {
+				value = values[index]
+			}
+			if inside[index] {
+				value = max(value, 1)
+				insideCount[j]++
+			} else {
+				value = min(value, -1)
+			}
+			field[padded.Index(i+offsetX, j+1)] = value
+		}
+	}
+	if region.Wraps() {
+		if region.Y == 0 && 2*insideCount[0] > region.Width {
+			for i := range padded.Width {
+				field[padded.Index(i, 0)] = 1
+			}
+		}
+		if region.Y+region.Height == region.Grid.Height() && 2*insideCount[region.Height-1] > region.Width {
+			for i := range padded.Width {
+				field[padded.Index(i, padded.Height-1)] = 1
+			}
+		}
+	}
+
+	exteriors, holes, wrappingHoles := []Polygon{}, [][]LatLng{}, [][]LatLng{}
+	for _, contour := range contourLevel(padded, field, 0) {
+		ring := contour.Points
+		for i := range ring {
+			ring[i].Lat = max(-90, min(90, ring[i].Lat))
+		}
+		if math.Abs(ring[len(ring)-1].Lng-ring[0].Lng) < 180 {
+			if area := RingArea(ring); area > 0 {
+				exteriors = append(exteriors, Polygon{Exterior: ring, Area: area})
+			} else {
+				holes = append(holes, ring)
+			}
+			continue
+		}
+
+		// a ring around the globe is closed through the south pole, making it an exterior when the inside lies
+		// south of it and a hole when the inside lies north
+		closed := closeThroughPole(ring, -90)
+		if area := RingArea(closed); area > 0 {
+			exteriors = append(exteriors, Polygon{Exterior: closed, Area: area})
+		} else {
+			wrappingHoles = append(wrappingHoles, ring)
+		}
+	}
+
+	// each hole belongs to the smallest exterior containing it, and a ring around the globe outside any exterior
+	// instead bounds an area around the north pole
+	addHole := func(hole []LatLng) bool {
+		for i := range exteriors {
+			if ringContains(exteriors[i].Exterior, hole[0]) {
+				exteriors[i].Holes = append(exteriors[i].Holes, hole)
+				exteriors[i].Area += RingArea(hole)
+				return true
+			}
+		}
+		return false
+	}
+	sortExteriors := func() {
+		slices.SortFunc(exteriors, func(a, b Polygon) int {
+			return cmp.Compare(a.Area, b.Area)
+		})
+	}
+	sortExteriors()
+	for _, ring := range wrappingHoles {
+		if !addHole(closeThroughPole(ring, -90)) {
+			closed := closeThroughPole(ring, 90)
+			exteriors = append(exteriors, Polygon{Exterior: closed, Area: RingArea(closed)})
+		}
+	}
+	sortExteriors()
+	for _, hole := range holes {
+		addHole(hole)
+	}
+	return exteriors
+}
+
+// closeThroughPole closes a ring that runs around the globe, and so ends 360 degrees of longitude from where it
+// starts, by following the last meridian to a pole, the pole back to the first meridian, and that meridian back to
+// the start.
+func closeThroughPole(ring []LatLng, pole float64) []LatLng {
+	first, last := ring[0], ring[len(ring)-1]
+	closed := slices.Clone(ring)
+	return append(closed, LatLng{Lat: pole, Lng: last.Lng}, LatLng{Lat: pole, Lng: first.Lng}, first)
+}
+
+// ringContains reports whether a position lies inside a ring by counting the crossings of a ray running north,
+// trying the position on each side of the antimeridian since the ring's longitudes are unwrapped.
+func ringContains(ring []LatLng, p LatLng) bool {
+	minLng, maxLng := lngRange(ring)
+	for lng := p.Lng - 360*math.Ceil((p.Lng-minLng)/360); lng <= maxLng; lng += 360 {
+		inside := false
+		for i := 1; i < len(ring); i++ {
+			a, b := ring[i-1], ring[i]
+			if (a.Lng > lng) != (b.Lng > lng) {
+				lat := a.Lat + (lng-a.Lng)/(b.Lng-a.Lng)*(b.Lat-a.Lat)
+				if lat > p.Lat {
+					inside = !inside
+				}
+			}
+		}
+		if inside {
+			return true
+		}
+	}
+	return false
+}
+
+// polygonMask tests whether the pixel centres of a region lie inside any of a set of polygons. It scans the
+// centre latitude of each row once for the longitudes where the rings of each polygon cross it, which pair up
+// into spans of unwrapped longitude inside the polygon.
+type polygonMask struct {
+	region GridRegion
+	spans  [][][2]float64 // For each row of the region, the spans inside a polygon.
+}
+
+// newPolygonMask scans the rows of a region for the spans inside the polygons.
+func newPolygonMask(region GridRegion, polygons []Polygon) *polygonMask {
+	mask := &polygonMask{region: region, spans: make([][][2]float64, region.Height)}
+	crossings := []float64{}
+	for j := range region.Height {
+		lat := region.Position(0, float64(j)+0.5).Lat
+		for _, polygon := range polygons {
+			crossings = crossings[:0]
+			for _, ring := range append([][]LatLng{polygon.Exterior}, polygon.Holes...) {
+				for k := 1; k < len(ring); k++ {
+					a, b := ring[k-1], ring[k]
+					if (a.Lat > lat) != (b.Lat > lat) {
+						crossings = append(crossings, a.Lng+(lat-a.Lat)/(b.Lat-a.Lat)*(b.Lng-a.Lng))
+					}
+				}
+			}
+			slices.Sort(crossings)
+			for k := 1; k < len(crossings); k += 2 {
+				mask.spans[j] = append(mask.spans[j], [2]float64{crossings[k-1], crossings[k]})
+			}
+		}
+	}
+	return mask
+}
+
+// Contains reports whether the centre of pixel (i, j) of the region lies inside a polygon.
+func (m *polygonMask) Contains(i, j int) bool {
+	lng := m.region.Position(float64(i)+0.5, 0).Lng
+	for _, span := range m.spans[j] {
+		if shifted := lng + 360*math.Ceil((span[0]-lng)/360); shifted <= span[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// PolygonsBounds returns the smallest bounds covering a set of polygons, crossing the antimeridian where that is
+// narrower, or spanning every longitude when no meridian misses every polygon.
+func PolygonsBounds(polygons []Polygon) Bounds {
+	bounds := Bounds{West: -180, South: 90, East: 180, North: -90}
+	spans := [][2]float64{} // the unwrapped longitudes of each polygon, starting in [-180, 180)
+	for _, polygon := range polygons {
+		if len(polygon.Exterior) == 0 {
+			continue
+		}
+		span := [2]float64{math.Inf(1), math.Inf(-1)}
+		for _, p := range polygon.Exterior {
+			bounds.South, bounds.North = min(bounds.South, p.Lat), max(bounds.North, p.Lat)
+			span[0], span[1] = min(span[0], p.Lng), max(span[1], p.Lng)
+		}
+		if span[1]-span[0] >= 360 {
+			return Bounds{West: -180, South: bounds.South, East: 180, North: bounds.North}
+		}
+		shift := NormalizeLng(span[0]) - span[0]
+		spans = append(spans, [2]float64{span[0] + shift, span[1] + shift})
+	}
+
+	// the bounds run from the far side of the widest gap between the spans around the globe to its near side; gaps
+	// are taken from a second lap of the spans, by when every span that may reach into a gap has been passed
+	slices.SortFunc(spans, func(a, b [2]float64) int { return cmp.Compare(a[0], b[0]) })
+	laps := len(spans)
+	for i := range laps {
+		spans = append(spans, [2]float64{spans[i][0] + 360, spans[i][1] + 360})
+	}
+	reach, widest := math.Inf(-1), 0.0
+	for i, span := range spans {
+		if gap := span[0] - reach; i >= laps && gap > widest {
+			widest = gap
+			bounds.West, bounds.East = NormalizeLng(span[0]), NormalizeLng(reach)
+			if bounds.East == -180 {
+				bounds.East = 180
+			}
+		}
+		reach = max(reach, span[1])
+	}
+	return bounds
+}
@@ -0,0 +1,89 @@
+package gebco
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// ProfileSample is a single sample of a depth profile.
+type ProfileSample struct {
+	Distance    float64 `json:"distance"` // Distance in metres along the path from the first waypoint.
+	Position    LatLng  `json:"position"`
+	GebcoSample         // The values of the pixel containing Position.
+}
+
+// WriteProfileCsv writes the samples of a profile as CSV with a header row, one row per sample.
+func WriteProfileCsv(w io.Writer, samples []ProfileSample) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"distance", "lat", "lng", "ice", "sub-ice", "tid"}); err != nil {
+		return err
+	}
+	for _, sample := range samples {
+		err := writer.Write([]string{
+			strconv.FormatFloat(sample.Distance, 'f', 1, 64),
+			strconv.FormatFloat(sample.Position.Lat, 'f', 6, 64),
+			strconv.FormatFloat(sample.Position.Lng, 'f', 6, 64),
+			strconv.Itoa(int(sample.Ice)),
+			strconv.Itoa(int(sample.SubIce)),
+			strconv.Itoa(int(sample.Tid)),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Profile samples a grid along a path of waypoints joined by great-circle segments, producing a depth
+// cross-section of the route. Samples are placed every spacing metres along each segment, restarting at each
+// waypoint so that every waypoint is sampled exactly. A spacing of zero or less uses the north-south size of
+// one grid pixel. Consecutive waypoints may not be antipodal, as no single great circle joins them.
+func Profile(grid *Grid, waypoints []LatLng, spacing float64) ([]ProfileSample, error) {
+	if len(waypoints) < 2 {
+		return nil, fmt.Errorf("a profile needs at least two waypoints, got %d", len(waypoints))
+	}
+	for i := 1; i < len(waypoints); i++ {
+		if antipodal(waypoints[i-1], waypoints[i]) {
+			return nil, fmt.Errorf("waypoints %v and %v are antipodal: add a waypoint between them to choose the great circle", waypoints[i-1], waypoints[i])
+		}
+	}
+	if spacing <= 0 {
+		spacing = grid.PixelSpacing()
+	}
+
+	samples := []ProfileSample{}
+	appendSample := func(distance float64, position LatLng) error {
+		value, err := grid.SampleAt(position)
+		if err != nil {
+			return err
+		}
+		samples = append(samples, ProfileSample{Distance: distance, Position: position, GebcoSample: value})
+		return nil
+	}
+
+	travelled := 0.0
+	if err := appendSample(0, waypoints[0]); err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(waypoints); i++ {
+		start, end := waypoints[i-1], waypoints[i]
+		length := Distance(start, end)
+		steps := int(math.Ceil(length / spacing))
+		for step := 1; step < steps; step++ {
+			along := float64(step) * spacing
+			if err := appendSample(travelled+along, Intermediate(start, end, along/length)); err != nil {
+				return nil, err
+			}
+		}
+		travelled += length
+		if err := appendSample(travelled, end); err != nil {
+			return nil, err
+		}
+	}
+
+	return samples, nil
+}
@@ -0,0 +1,130 @@
+package gebco
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	cases := []struct {
+		name     string
+		a, b     LatLng
+		expected float64
+	}{
+		{"same point", LatLng{10, 20}, LatLng{10, 20}, 0},
+		{"one degree of latitude", LatLng{0, 0}, LatLng{1, 0}, 111195.08},
+		{"across antimeridian", LatLng{0, 179.5}, LatLng{0, -179.5}, 111195.08},
+		{"pole to pole", LatLng{90, 0}, LatLng{-90, 0}, math.Pi * EarthRadius},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := Distance(c.a, c.b)
+			if math.Abs(actual-c.expected) > 0.01 {
+				t.Errorf("expected %f, got %f", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseBounds(t *testing.T) {
+	valid := map[string]Bounds{
+		"-180,-90,180,90":   GlobalBounds,
+		"-10, -5, 20, 5":    {West: -10, South: -5, East: 20, North: 5},
+		"170,-10,-170,10":   {West: 170, South: -10, East: -170, North: 10},
+		"180,-10,-170,10":   {West: -180, South: -10, East: -170, North: 10},
+		"170,-10,-180,10":   {West: 170, South: -10, East: 180, North: 10},
+		"-180,-10,-179,10":  {West: -180, South: -10, East: -179, North: 10},
+		"179.5,0,179.25,10": {West: 179.5, South: 0, East: 179.25, North: 10},
+	}
+	for s, expected := range valid {
+		bounds, err := ParseBounds(s)
+		if err != nil {
+			t.Errorf("failed to parse %q: %v", s, err)
+		} else if bounds != expected {
+			t.Errorf("expected %q to parse as %+v, got %+v", s, expected, bounds)
+		}
+	}
+	if width := (Bounds{West: 179.5, East: 179.25}).Width(); width != 359.75 {
+		t.Errorf("expected bounds crossing the antimeridian to be 359.75 degrees wide, got %g", width)
+	}
+
+	for _, s := range []string{
+		"10,-5,10,5",     // no width
+		"0,-5,360,5",     // east out of range, and no width
+		"-180,-5,-180,5", // no width
+		"180,-5,-180,5",  // the globe backwards
+		"180,-5,180,5",   // no width
+		"-190,-5,10,5",   // west out of range
+		"10,-5,200,5",    // east out of range
+		"10,5,20,-5",     // south above north
+		"10,-95,20,5",    // south out of range
+		"10,-5,20",       // missing north
+		"10,-5,20,north", // not a number
+		"NaN,-5,20,5",    // west not a number
+		"10,NaN,20,5",    // south not a number
+	} {
+		if bounds, err := ParseBounds(s); err == nil {
+			t.Errorf("expected an error for %q, got %+v", s, bounds)
+		}
+	}
+}
+
+func TestProfile(t *testing.T) {
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		return GebcoSample{Ice: int16(-100 * x), SubIce: int16(-100 * x), Tid: GebcoTypeInterpolated}
+	})
+
+	waypoints := []LatLng{{Lat: 0, Lng: -175}, {Lat: 0, Lng: -145}, {Lat: 0, Lng: -135}}
+	samples, err := Profile(dataset.Grid(), waypoints, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// one grid pixel is ten degrees, so the 30 degree segment gives three samples and the 10 degree segment one
+	if len(samples) != 5 {
+		t.Fatalf("expected 5 samples, got %d", len(samples))
+	}
+	expectedIce := []int16{0, -100, -200, -300, -400}
+	for i, sample := range samples {
+		if sample.Ice != expectedIce[i] {
+			t.Errorf("sample %d: expected ice %d, got %d", i, expectedIce[i], sample.Ice)
+		}
+		expectedDistance := float64(i) * dataset.Grid().PixelSpacing()
+		if math.Abs(sample.Distance-expectedDistance) > 1 {
+			t.Errorf("sample %d: expected distance %f, got %f", i, expectedDistance, sample.Distance)
+		}
+	}
+	if samples[len(samples)-1].Position != waypoints[2] {
+		t.Errorf("expected profile to end at %v, got %v", waypoints[2], samples[len(samples)-1].Position)
+	}
+
+	var csv strings.Builder
+	err = WriteProfileCsv(&csv, []ProfileSample{
+		{Distance: 0, Position: LatLng{Lat: 10, Lng: -175}, GebcoSample: GebcoSample{Ice: 12, SubIce: -30, Tid: GebcoTypeLand}},
+		{Distance: 1234.56, Position: LatLng{Lat: 10.5, Lng: -174.25}, GebcoSample: GebcoSample{Ice: -100, SubIce: -100, Tid: GebcoTypeInterpolated}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedCsv := "distance,lat,lng,ice,sub-ice,tid\n" +
+		"0.0,10.000000,-175.000000,12,-30,0\n" +
+		"1234.6,10.500000,-174.250000,-100,-100,41\n"
+	if csv.String() != expectedCsv {
+		t.Errorf("expected CSV %q, got %q", expectedCsv, csv.String())
+	}
+
+	// no single great circle joins antipodal waypoints, unless a waypoint between them chooses one
+	if _, err := Profile(dataset.Grid(), []LatLng{{Lat: 10, Lng: 20}, {Lat: -10, Lng: -160}}, 0); err == nil {
+		t.Errorf("expected an error for antipodal waypoints")
+	}
+	samples, err = Profile(dataset.Grid(), []LatLng{{Lat: 10, Lng: 20}, {Lat: 80, Lng: 20}, {Lat: -10, Lng: -160}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sample := range samples {
+		if math.IsNaN(sample.Position.Lat) || math.IsNaN(sample.Position.Lng) {
+			t.Fatalf("expected a defined profile through a waypoint between antipodes, got %+v", sample)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package gebco
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	wgs84SemiMajor    float64 = 6378137.0          // The WGS84 ellipsoid semi-major axis in metres.
+	wgs84Eccentricity float64 = 0.0818191908426215 // The WGS84 ellipsoid first eccentricity, sqrt(f(2-f)).
+)
+
+// Projection converts between geographic positions and the planar coordinates of a map projection.
+type Projection interface {
+	EPSG() int                                // The EPSG code of the projected coordinate reference system.
+	Forward(p LatLng) (x, y float64)          // Projects a position to map coordinates.
+	Inverse(x, y float64) (p LatLng, ok bool) // Unprojects map coordinates, ok is false outside the projection domain.
+}
+
+// ProjectionForEpsg returns the projection for one of the supported EPSG codes: 4326 (WGS84 plate carrée, in
+// degrees), 3857 (Web Mercator), 3413 (NSIDC north polar stereographic) or 3031 (Antarctic polar stereographic).
+func ProjectionForEpsg(code int) (Projection, error) {
+	switch code {
+	case 4326:
+		return PlateCarree{}, nil
+	case 3857:
+		return WebMercator{}, nil
+	case 3413:
+		return NewPolarStereographic(3413, 70, -45), nil
+	case 3031:
+		return NewPolarStereographic(3031, -71, 0), nil
+	default:
+		return nil, fmt.Errorf("unsupported EPSG code %d (expected 4326, 3857, 3413 or 3031)", code)
+	}
+}
+
+// PlateCarree is the geographic WGS84 coordinate system (EPSG:4326), with x as longitude and y as latitude in
+// degrees.
+type PlateCarree struct{}
+
+func (PlateCarree) EPSG() int {
+	return 4326
+}
+
+func (PlateCarree) Forward(p LatLng) (x, y float64) {
+	return p.Lng, p.Lat
+}
+
+func (PlateCarree) Inverse(x, y float64) (LatLng, bool) {
+	return LatLng{Lat: y, Lng: NormalizeLng(x)}, y >= -90 && y <= 90
+}
+
+// WebMercator is the spherical Mercator projection used by web maps (EPSG:3857), in metres.
+type WebMercator struct{}
+
+func (WebMercator) EPSG() int {
+	return 3857
+}
+
+func (WebMercator) Forward(p LatLng) (x, y float64) {
+	lat := max(-WebMercatorMaxLat, min(WebMercatorMaxLat, p.Lat))
+	return wgs84SemiMajor * radians(p.Lng), wgs84SemiMajor * math.Log(math.Tan(math.Pi/4+radians(lat)/2))
+}
+
+func (WebMercator) Inverse(x, y float64) (LatLng, bool) {
+	lng := degrees(x / wgs84SemiMajor)
+	lat := degrees(math.Atan(math.Sinh(y / wgs84SemiMajor)))
+	return LatLng{Lat: lat, Lng: NormalizeLng(lng)}, math.Abs(lng) <= 180
+}
+
+// PolarStereographic is the ellipsoidal polar stereographic projection on WGS84 with a latitude of true scale,
+// centred on the north pole when that latitude is positive and on the south pole when it is negative.
+type PolarStereographic struct {
+	epsg   int
+	south  bool
+	lng0   float64 // the central meridian in radians
+	scale  float64 // a * m_c / t_c for the latitude of true scale
+	series [4]float64
+}
+
+// NewPolarStereographic creates a polar stereographic projection with the given latitude of true scale and
+// central meridian in degrees.
+func NewPolarStereographic(epsg int, trueScaleLat float64, centralLng float64) PolarStereographic {
+	e := wgs84Eccentricity
+	e2 := e * e
+	e4, e6, e8 := e2*e2, e2*e2*e2, e2*e2*e2*e2
+
+	south := trueScaleLat < 0
+	latC := radians(math.Abs(trueScaleLat))
+	mc := math.Cos(latC) / math.Sqrt(1-e2*math.Sin(latC)*math.Sin(latC))
+	tc := polarT(latC)
+	lng0 := radians(centralLng)
+	if south {
+		lng0 = -lng0
+	}
+
+	return PolarStereographic{
+		epsg:  epsg,
+		south: south,
+		lng0:  lng0,
+		scale: wgs84SemiMajor * mc / tc,
+		series: [4]float64{
+			e2/2 + 5*e4/24 + e6/12 + 13*e8/360,
+			7*e4/48 + 29*e6/240 + 811*e8/11520,
+			7*e6/120 + 81*e8/1120,
+			4279 * e8 / 161280,
+		},
+	}
+}
+
+// polarT is the isometric colatitude function t of Snyder (1987) for a latitude in radians.
+func polarT(lat float64) float64 {
+	e := wgs84Eccentricity
+	sinLat := math.Sin(lat)
+	return math.Tan(math.Pi/4-lat/2) / math.Pow((1-e*sinLat)/(1+e*sinLat), e/2)
+}
+
+func (p PolarStereographic) EPSG() int {
+	return p.epsg
+}
+
+func (p PolarStereographic) Forward(position LatLng) (x, y float64) {
+	lat, lng := radians(position.Lat), radians(position.Lng)
+	if p.south {
+		lat, lng = -lat, -lng
+	}
+	rho := p.scale * polarT(lat)
+	x = rho * math.Sin(lng-p.lng0)
+	y = -rho * math.Cos(lng-p.lng0)
+	if p.south {
+		x, y = -x, -y
+	}
+	return x, y
+}
+
+func (p PolarStereographic) Inverse(x, y float64) (LatLng, bool) {
+	if p.south {
+		x, y = -x, -y
+	}
+	rho := math.Hypot(x, y)
+	t := rho / p.scale
+	chi := math.Pi/2 - 2*math.Atan(t)
+	lat := chi + p.series[0]*math.Sin(2*chi) + p.series[1]*math.Sin(4*chi) + p.series[2]*math.Sin(6*chi) + p.series[3]*math.Sin(8*chi)
+	lng := p.lng0 + math.Atan2(x, -y)
+	if p.south {
+		lat, lng = -lat, -lng
+	}
+	// the projection covers the globe except the opposite pole, but degrades far from its own pole
+	return LatLng{Lat: degrees(lat), Lng: NormalizeLng(degrees(lng))}, chi > 0
+}
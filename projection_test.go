@@ -0,0 +1,73 @@
+package gebco
+
+import (
+	"math"
+	"testing"
+)
+
+func TestProjectionRoundTrip(t *testing.T) {
+	cases := []struct {
+		name     string
+		epsg     int
+		position LatLng
+	}{
+		{"plate carree", 4326, LatLng{Lat: -33.9, Lng: 151.2}},
+		{"web mercator", 3857, LatLng{Lat: 51.5, Lng: -0.1}},
+		{"arctic", 3413, LatLng{Lat: 78.2, Lng: 15.6}},
+		{"arctic far side", 3413, LatLng{Lat: 61.0, Lng: 135}},
+		{"antarctic", 3031, LatLng{Lat: -77.8, Lng: 166.7}},
+		{"antarctic far side", 3031, LatLng{Lat: -55.0, Lng: -60}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			projection, err := ProjectionForEpsg(c.epsg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, ok := projection.Inverse(projection.Forward(c.position))
+			if !ok {
+				t.Fatalf("expected %v to be inside the projection domain", c.position)
+			}
+			if math.Abs(actual.Lat-c.position.Lat) > 1e-8 || math.Abs(actual.Lng-c.position.Lng) > 1e-8 {
+				t.Errorf("expected %v, got %v", c.position, actual)
+			}
+		})
+	}
+}
+
+func TestPolarStereographicAxes(t *testing.T) {
+	cases := []struct {
+		name             string
+		epsg             int
+		position         LatLng
+		expectedX        float64
+		expectedNegative bool // whether the point lies on the negative y axis
+	}{
+		{"north pole", 3413, LatLng{Lat: 90, Lng: 0}, 0, false},
+		{"arctic central meridian", 3413, LatLng{Lat: 70, Lng: -45}, 0, true},
+		{"south pole", 3031, LatLng{Lat: -90, Lng: 0}, 0, false},
+		{"antarctic 180", 3031, LatLng{Lat: -71, Lng: 180}, 0, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			projection, _ := ProjectionForEpsg(c.epsg)
+			x, y := projection.Forward(c.position)
+			if math.Abs(x-c.expectedX) > 1e-6 {
+				t.Errorf("expected x %f, got %f", c.expectedX, x)
+			}
+			if c.expectedNegative != (y < -1) {
+				t.Errorf("expected y negative %v, got %f", c.expectedNegative, y)
+			}
+		})
+	}
+
+	// the scale is true along the standard parallel, so a degree of longitude there has its ellipsoidal length
+	projection := NewPolarStereographic(3031, -71, 0)
+	x0, y0 := projection.Forward(LatLng{Lat: -71, Lng: 0})
+	x1, y1 := projection.Forward(LatLng{Lat: -71, Lng: 0.001})
+	lat := radians(71)
+	expected := wgs84SemiMajor * math.Cos(lat) / math.Sqrt(1-wgs84Eccentricity*wgs84Eccentricity*math.Sin(lat)*math.Sin(lat)) * radians(0.001)
+	if actual := math.Hypot(x1-x0, y1-y0); math.Abs(actual-expected) > 1e-3 {
+		t.Errorf("expected %f m per 0.001 degrees at the standard parallel, got %f", expected, actual)
+	}
+}
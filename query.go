@@ -0,0 +1,110 @@
+package gebco
+
+import (
+	"cmp"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// queryChunkSize is the most positions of a tile a worker of QueryPoints samples at once, so that positions
+// clustered in a few tiles are still shared between the workers.
+const queryChunkSize = 1024
+
+// QueryOptions configures QueryPoints.
+type QueryOptions struct {
+	Surface       GebcoDataType // The surface (ice, sub-ice) to interpolate at each position.
+	Interpolation Interpolation // How to interpolate the surface between pixel centres.
+	Workers       int           // The number of goroutines sampling tiles, or 0 for one per processor.
+}
+
+// PointValues holds the values of a grid at a queried position.
+type PointValues struct {
+	GebcoSample          // The values of the pixel containing the position.
+	Interpolated float64 `json:"interpolated"` // The surface interpolated at the position.
+}
+
+// QueryPoints samples a grid at many positions, returning their values in the order of the positions. The positions
+// are visited tile by tile, in runs of the positions falling in the same tile split into chunks shared between
+// workers, so that each tile is decompressed about once however scattered the positions are, provided the tile
+// cache of the dataset holds a few tiles per worker for the neighbours interpolation reaches into, and positions
+// clustered in one tile are still sampled in parallel. The first error stops the query.
+func QueryPoints(grid *Grid, positions []LatLng, opts QueryOptions) ([]PointValues, error) {
+	if opts.Surface == GebcoDataTypeId {
+		return nil, fmt.Errorf("cannot interpolate type IDs")
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	// order the positions by the tile containing them, then row by row within it
+	dimensions := grid.access.Layer().Dimensions
+	tileWidth, tileHeight := dimensions[0].TileSize, dimensions[1].TileSize
+	tilesAcross := (grid.width + tileWidth - 1) / tileWidth
+	type query struct {
+		index, tile, x, y int
+	}
+	queries := make([]query, len(positions))
+	for i, p := range positions {
+		x, y := grid.Pixel(p)
+		queries[i] = query{index: i, tile: (y/tileHeight)*tilesAcross + x/tileWidth, x: x, y: y}
+	}
+	slices.SortFunc(queries, func(a, b query) int {
+		return cmp.Or(cmp.Compare(a.tile, b.tile), cmp.Compare(a.y, b.y), cmp.Compare(a.x, b.x))
+	})
+
+	values := make([]PointValues, len(positions))
+	runs := make(chan []query)
+	errs := make([]error, workers)
+	done := make(chan struct{})
+	var stop sync.Once
+	wg := sync.WaitGroup{}
+	for worker := range workers {
+		wg.Go(func() {
+			buf := grid.newSampleBuffer()
+			for run := range runs {
+				for _, q := range run {
+					sample, err := grid.sampleInto(q.x, q.y, buf)
+					if err != nil {
+						errs[worker] = err
+						stop.Do(func() { close(done) })
+						return
+					}
+					interpolated := sample.Value(opts.Surface)
+					if opts.Interpolation != InterpolateNearest {
+						interpolated, err = grid.interpolateInto(positions[q.index], opts.Surface, opts.Interpolation, buf)
+						if err != nil {
+							errs[worker] = err
+							stop.Do(func() { close(done) })
+							return
+						}
+					}
+					values[q.index] = PointValues{GebcoSample: sample, Interpolated: interpolated}
+				}
+			}
+		})
+	}
+feed:
+	for start := 0; start < len(queries); {
+		end := start + 1
+		for end < len(queries) && end-start < queryChunkSize && queries[end].tile == queries[start].tile {
+			end++
+		}
+		select {
+		case runs <- queries[start:end]:
+		case <-done:
+			break feed
+		}
+		start = end
+	}
+	close(runs)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,73 @@
+package gebco
+
+import (
+	"testing"
+)
+
+func TestQueryPoints(t *testing.T) {
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		return GebcoSample{Ice: int16(100*x - 1000*y), SubIce: int16(-1000 * y), Tid: GebcoTypeId(x % 3)}
+	})
+	grid := dataset.Grid()
+
+	// positions scattered over the tiles, in no particular order, with a repeat and one across the antimeridian
+	positions := []LatLng{{Lat: 80, Lng: 170}, {Lat: -45, Lng: -30}, {Lat: 12, Lng: 3}, {Lat: 80, Lng: 170}, {Lat: -89, Lng: -179}, {Lat: 33, Lng: 181}}
+	values, err := QueryPoints(grid, positions, QueryOptions{Surface: GebcoDataIce, Interpolation: InterpolateBilinear, Workers: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != len(positions) {
+		t.Fatalf("expected %d values, got %d", len(positions), len(values))
+	}
+	for i, p := range positions {
+		sample, err := grid.SampleAt(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		interpolated, err := grid.Interpolate(p, GebcoDataIce, InterpolateBilinear)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if values[i] != (PointValues{GebcoSample: sample, Interpolated: interpolated}) {
+			t.Errorf("expected %+v and %g at %v, got %+v", sample, interpolated, p, values[i])
+		}
+	}
+
+	// the nearest interpolation is the value of the pixel itself
+	values, err = QueryPoints(grid, positions[:1], QueryOptions{Surface: GebcoDataSubIce})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values[0].Interpolated != float64(values[0].SubIce) {
+		t.Errorf("expected the nearest sub-ice value %d, got %g", values[0].SubIce, values[0].Interpolated)
+	}
+	if _, err := QueryPoints(grid, positions, QueryOptions{Surface: GebcoDataTypeId}); err == nil {
+		t.Errorf("expected an error interpolating type IDs")
+	}
+}
+
+func TestQueryPointsClustered(t *testing.T) {
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		return GebcoSample{Ice: int16(100*x - 1000*y), SubIce: int16(-1000 * y), Tid: GebcoTypeMultiBeam}
+	})
+	grid := dataset.Grid()
+
+	// more positions in one tile than a chunk, spread over its pixels
+	positions := make([]LatLng, 3*queryChunkSize+7)
+	for i := range positions {
+		positions[i] = LatLng{Lat: 5 + float64(i%50), Lng: 5 + float64(i%40)}
+	}
+	values, err := QueryPoints(grid, positions, QueryOptions{Surface: GebcoDataIce, Workers: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range positions {
+		sample, err := grid.SampleAt(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if values[i].GebcoSample != sample {
+			t.Fatalf("expected %+v at %v, got %+v", sample, p, values[i])
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package gebco
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/gracefulearth/gopixi"
+)
+
+// Raster is an in-memory grid of float32 bands georeferenced in a map projection. Pixel (0, 0) is the north-west
+// corner and missing values are NaN.
+type Raster struct {
+	Projection  Projection
+	Width       int
+	Height      int
+	MinX        float64 // The west edge of the raster in projection units.
+	MaxY        float64 // The north edge of the raster in projection units.
+	PixelWidth  float64 // The east-west size of a pixel in projection units.
+	PixelHeight float64 // The north-south size of a pixel in projection units.
+	BandNames   []string
+	Bands       [][]float32 // One row-major slice of Width*Height values per band.
+}
+
+// NewRaster allocates a raster with the given bands filled with NaN.
+func NewRaster(projection Projection, minX, maxY, pixelWidth, pixelHeight float64, width, height int, bandNames ...string) *Raster {
+	raster := &Raster{
+		Projection:  projection,
+		Width:       width,
+		Height:      height,
+		MinX:        minX,
+		MaxY:        maxY,
+		PixelWidth:  pixelWidth,
+		PixelHeight: pixelHeight,
+		BandNames:   bandNames,
+		Bands:       make([][]float32, len(bandNames)),
+	}
+	for i := range raster.Bands {
+		band := make([]float32, width*height)
+		for j := range band {
+			band[j] = float32(math.NaN())
+		}
+		raster.Bands[i] = band
+	}
+	return raster
+}
+
+// PixelCenter returns the projected coordinates of the centre of a pixel.
+func (r *Raster) PixelCenter(i, j int) (x, y float64) {
+	return r.MinX + (float64(i)+0.5)*r.PixelWidth, r.MaxY - (float64(j)+0.5)*r.PixelHeight
+}
+
+// ReprojectOptions configures Reproject.
+type ReprojectOptions struct {
+	Projection    Projection
+	MinX, MinY    float64 // The south-west corner of the output in projection units.
+	MaxX, MaxY    float64 // The north-east corner of the output in projection units.
+	Resolution    float64 // The size of an output pixel in projection units.
+	Interpolation Interpolation
+	Channels      []GebcoDataType // The channels to resample, one output band each.
+}
+
+// Reproject resamples a grid into a raster in another projection by inverse mapping: the centre of every output
+// pixel is unprojected to a geographic position and the grid is interpolated there. Output pixels outside the
+// projection domain are NaN.
+func Reproject(grid *Grid, opts ReprojectOptions) (*Raster, error) {
+	if opts.Resolution <= 0 || opts.MaxX <= opts.MinX || opts.MaxY <= opts.MinY {
+		return nil, fmt.Errorf("invalid reprojection extent (%g,%g)-(%g,%g) at resolution %g", opts.MinX, opts.MinY, opts.MaxX, opts.MaxY, opts.Resolution)
+	}
+	if len(opts.Channels) == 0 {
+		return nil, fmt.Errorf("no channels to reproject")
+	}
+	bandNames := make([]string, len(opts.Channels))
+	for i, channel := range opts.Channels {
+		if channel == GebcoDataTypeId && !grid.HasTid() {
+			return nil, fmt.Errorf("layer %s has no type ID channel to reproject", grid.Name())
+		}
+		name, err := channel.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		bandNames[i] = string(name)
+	}
+
+	width := int(math.Ceil((opts.MaxX - opts.MinX) / opts.Resolution))
+	height := int(math.Ceil((opts.MaxY - opts.MinY) / opts.Resolution))
+	raster := NewRaster(opts.Projection, opts.MinX, opts.MaxY, opts.Resolution, opts.Resolution, width, height, bandNames...)
+
+	buf := grid.newSampleBuffer()
+	for j := range height {
+		for i := range width {
+			position, ok := opts.Projection.Inverse(raster.PixelCenter(i, j))
+			if !ok {
+				continue
+			}
+			for band, channel := range opts.Channels {
+				value, err := grid.interpolateInto(position, channel, opts.Interpolation, buf)
+				if err != nil {
+					return nil, err
+				}
+				raster.Bands[band][j*width+i] = float32(value)
+			}
+		}
+	}
+	return raster, nil
+}
+
+// WritePixi writes the raster to a new Pixi file as a single layer of float32 channels named after its bands,
+// recording its projection and georeferencing in the file tags.
+func (r *Raster) WritePixi(w io.WriteSeeker, layerName string, tileSize int, opts ...gopixi.LayerOption) error {
+	summary := &gopixi.Pixi{
+		Header: gopixi.NewHeader(binary.NativeEndian, gopixi.OffsetSize8),
+	}
+	if err := summary.Header.WriteHeader(w); err != nil {
+		return err
+	}
+
+	err := summary.AppendTags(w, map[string]string{
+		"epsg":        strconv.Itoa(r.Projection.EPSG()),
+		"minX":        strconv.FormatFloat(r.MinX, 'g', -1, 64),
+		"maxY":        strconv.FormatFloat(r.MaxY, 'g', -1, 64),
+		"pixelWidth":  strconv.FormatFloat(r.PixelWidth, 'g', -1, 64),
+		"pixelHeight": strconv.FormatFloat(r.PixelHeight, 'g', -1, 64),
+	})
+	if err != nil {
+		return err
+	}
+
+	channels := make(gopixi.ChannelSet, len(r.Bands))
+	for i, name := range r.BandNames {
+		channels[i] = gopixi.Channel{Name: name, Type: gopixi.ChannelFloat32}
+	}
+	layer := gopixi.NewLayer(layerName,
+		gopixi.DimensionSet{
+			{Name: "x", TileSize: min(tileSize, r.Width), Size: r.Width},
+			{Name: "y", TileSize: min(tileSize, r.Height), Size: r.Height}},
+		channels,
+		opts...,
+	)
+
+	iterator := gopixi.NewTileOrderWriteIterator(w, summary.Header, layer)
+	sample := make(gopixi.Sample, len(r.Bands))
+	return summary.AppendIterativeLayer(w, layer, iterator, func(dstIterator gopixi.IterativeLayerWriter) error {
+		for dstIterator.Next() {
+			coord := dstIterator.Coordinate()
+			for band := range r.Bands {
+				value := float32(math.NaN())
+				if coord[0] < r.Width && coord[1] < r.Height {
+					value = r.Bands[band][coord[1]*r.Width+coord[0]]
+				}
+				sample[band] = value
+			}
+			dstIterator.SetSample(sample)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,158 @@
+package gebco
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestReproject(t *testing.T) {
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		return GebcoSample{Ice: int16(100*y + x), SubIce: int16(-y)}
+	})
+
+	raster, err := Reproject(dataset.Grid(), ReprojectOptions{
+		Projection: PlateCarree{},
+		MinX:       -180, MinY: 50, MaxX: -160, MaxY: 90,
+		Resolution: 10,
+		Channels:   []GebcoDataType{GebcoDataIce, GebcoDataSubIce},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raster.Width != 2 || raster.Height != 4 {
+		t.Fatalf("expected a 2x4 raster, got %dx%d", raster.Width, raster.Height)
+	}
+	for j := range raster.Height {
+		for i := range raster.Width {
+			if actual := raster.Bands[0][j*raster.Width+i]; actual != float32(100*j+i) {
+				t.Errorf("expected ice %d at (%d, %d), got %f", 100*j+i, i, j, actual)
+			}
+			if actual := raster.Bands[1][j*raster.Width+i]; actual != float32(-j) {
+				t.Errorf("expected sub-ice %d at (%d, %d), got %f", -j, i, j, actual)
+			}
+		}
+	}
+
+	// pixels beyond the pole are outside the projection domain
+	raster, err = Reproject(dataset.Grid(), ReprojectOptions{
+		Projection: PlateCarree{},
+		MinX:       -180, MinY: 80, MaxX: -170, MaxY: 100,
+		Resolution: 10,
+		Channels:   []GebcoDataType{GebcoDataIce},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsNaN(float64(raster.Bands[0][0])) || raster.Bands[0][1] != 0 {
+		t.Errorf("expected [NaN 0], got %v", raster.Bands[0])
+	}
+}
+
+func TestWriteGeoTiff(t *testing.T) {
+	raster := NewRaster(NewPolarStereographic(3031, -71, 0), -1000, 2000, 500, 500, 3, 20, "ice", "sub-ice")
+	for i := range raster.Bands[0] {
+		raster.Bands[0][i] = float32(i)
+		raster.Bands[1][i] = float32(-i)
+	}
+	raster.Bands[1][5] = float32(math.NaN())
+
+	var buf bytes.Buffer
+	if err := raster.WriteGeoTiff(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if !bytes.Equal(data[:4], []byte{'I', 'I', 42, 0}) {
+		t.Fatalf("expected a little-endian classic TIFF header, got %v", data[:4])
+	}
+	fields, _ := readTestTiffFields(t, data, uint64(binary.LittleEndian.Uint32(data[4:])), false)
+
+	expected := map[uint16][]uint64{
+		tagImageWidth:      {3},
+		tagImageLength:     {20},
+		tagBitsPerSample:   {32, 32},
+		tagCompression:     {uint64(tiffCompressionDeflate)},
+		tagSamplesPerPixel: {2},
+		tagSampleFormat:    {3, 3},
+		tagGeoKeyDirectory: {1, 1, 0, 3, 1024, 0, 1, 1, 1025, 0, 1, 1, 3072, 0, 1, 3031},
+	}
+	for tag, values := range expected {
+		if !slices.Equal(fields[tag], values) {
+			t.Errorf("expected tag %d to be %v, got %v", tag, values, fields[tag])
+		}
+	}
+
+	offsets, counts := fields[tagStripOffsets], fields[tagStripByteCounts]
+	if len(offsets) != 2 || len(counts) != 2 {
+		t.Fatalf("expected 2 strips, got %d offsets and %d counts", len(offsets), len(counts))
+	}
+	var pixels []byte
+	for i := range offsets {
+		reader, err := zlib.NewReader(bytes.NewReader(data[offsets[i] : offsets[i]+counts[i]]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		strip, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pixels = append(pixels, strip...)
+	}
+	if len(pixels) != 3*20*2*4 {
+		t.Fatalf("expected %d bytes of pixels, got %d", 3*20*2*4, len(pixels))
+	}
+	for i := range 3 * 20 {
+		ice := math.Float32frombits(binary.LittleEndian.Uint32(pixels[8*i:]))
+		subIce := math.Float32frombits(binary.LittleEndian.Uint32(pixels[8*i+4:]))
+		if ice != raster.Bands[0][i] {
+			t.Errorf("expected ice %f at %d, got %f", raster.Bands[0][i], i, ice)
+		}
+		if subIce != raster.Bands[1][i] && !(math.IsNaN(float64(subIce)) && i == 5) {
+			t.Errorf("expected sub-ice %f at %d, got %f", raster.Bands[1][i], i, subIce)
+		}
+	}
+}
+
+// readTestTiffFields decodes the integer values of the fields of the little-endian TIFF or BigTIFF IFD at offset
+// and returns them with the offset of the next IFD.
+func readTestTiffFields(t *testing.T, data []byte, offset uint64, big bool) (map[uint16][]uint64, uint64) {
+	t.Helper()
+	countSize, entrySize, inlineSize := 2, 12, 4
+	if big {
+		countSize, entrySize, inlineSize = 8, 20, 8
+	}
+	readOffset := func(b []byte, size int) uint64 {
+		if size == 8 {
+			return binary.LittleEndian.Uint64(b)
+		} else if size == 4 {
+			return uint64(binary.LittleEndian.Uint32(b))
+		}
+		return uint64(binary.LittleEndian.Uint16(b))
+	}
+
+	fields := map[uint16][]uint64{}
+	entries := int(readOffset(data[offset:], countSize))
+	for e := range entries {
+		entry := data[int(offset)+countSize+entrySize*e:]
+		tag := binary.LittleEndian.Uint16(entry)
+		kind := binary.LittleEndian.Uint16(entry[2:])
+		count := int(readOffset(entry[4:], inlineSize))
+		size := map[uint16]int{tiffAscii: 1, tiffShort: 2, tiffLong: 4, tiffDouble: 8, tiffLong8: 8}[kind]
+		values := entry[4+inlineSize : 4+2*inlineSize]
+		if size*count > inlineSize {
+			values = data[readOffset(values, inlineSize):]
+		}
+		for i := range count {
+			switch kind {
+			case tiffShort, tiffLong, tiffLong8:
+				fields[tag] = append(fields[tag], readOffset(values[size*i:], size))
+			}
+		}
+	}
+	next := readOffset(data[int(offset)+countSize+entrySize*entries:], inlineSize)
+	return fields, next
+}
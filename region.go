@@ -0,0 +1,157 @@
+package gebco
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// GridRegion is a rectangle of whole pixels of a grid. Columns continue across the antimeridian, so X+Width may
+// exceed the width of the grid.
+type GridRegion struct {
+	Grid   *Grid
+	X, Y   int // The top-left pixel of the region in the grid.
+	Width  int
+	Height int
+}
+
+// Region returns the smallest region of whole pixels covering the bounds, spanning the whole grid in longitude
+// when the bounds do.
+func (g *Grid) Region(b Bounds) (GridRegion, error) {
+	step := g.DegreesPerPixel()
+	x := int(math.Floor((b.West + 180) / step))
+	y := max(0, int(math.Floor((90-b.North)/step)))
+	width := min(g.width, int(math.Ceil((b.West+b.Width()+180)/step))-x)
+	height := min(g.height, int(math.Ceil((90-b.South)/step))) - y
+	if width <= 0 || height <= 0 {
+		return GridRegion{}, fmt.Errorf("region %+v contains no pixels of layer %s", b, g.Name())
+	}
+	return GridRegion{Grid: g, X: x, Y: y, Width: width, Height: height}, nil
+}
+
+// Wraps reports whether the region spans the whole globe in longitude, so that its last column neighbours its
+// first.
+func (r GridRegion) Wraps() bool {
+	return r.Width == r.Grid.width
+}
+
+// West returns the longitude of the west edge of the region.
+func (r GridRegion) West() float64 {
+	return float64(r.X)*r.Grid.DegreesPerPixel() - 180
+}
+
+// North returns the latitude of the north edge of the region.
+func (r GridRegion) North() float64 {
+	return 90 - float64(r.Y)*r.Grid.DegreesPerPixel()
+}
+
+// Position returns the position of fractional pixel coordinates within the region, where (0, 0) is the
+// north-west corner and (0.5, 0.5) the centre of the first pixel. Longitudes are not normalised, so they increase
+// continuously eastwards across the antimeridian.
+func (r GridRegion) Position(fx, fy float64) LatLng {
+	step := r.Grid.DegreesPerPixel()
+	return LatLng{Lat: r.North() - fy*step, Lng: r.West() + fx*step}
+}
+
+// Index returns the index of a pixel of the region in its row-major values.
+func (r GridRegion) Index(i, j int) int {
+	return j*r.Width + i
+}
+
+// ReadSamples reads the samples of every pixel of the region in row-major order.
+func (r GridRegion) ReadSamples() ([]GebcoSample, error) {
+	samples := make([]GebcoSample, r.Width*r.Height)
+	buf := r.Grid.newSampleBuffer()
+	for j := range r.Height {
+		for i := range r.Width {
+			sample, err := r.Grid.sampleInto(r.X+i, r.Y+j, buf)
+			if err != nil {
+				return nil, err
+			}
+			samples[r.Index(i, j)] = sample
+		}
+	}
+	return samples, nil
+}
+
+// ReadValues reads the values of a channel of every pixel of the region in row-major order.
+func (r GridRegion) ReadValues(data GebcoDataType) ([]float32, error) {
+	values := make([]float32, r.Width*r.Height)
+	buf := r.Grid.newSampleBuffer()
+	for j := range r.Height {
+		for i := range r.Width {
+			sample, err := r.Grid.sampleInto(r.X+i, r.Y+j, buf)
+			if err != nil {
+				return nil, err
+			}
+			values[r.Index(i, j)] = float32(sample.Value(data))
+		}
+	}
+	return values, nil
+}
+
+// Tiles splits the region into the parts of it lying in each tile of the layer behind its grid, in row-major
+// order. Pixel (i, j) of a part is pixel (part.X-r.X+i, part.Y-r.Y+j) of the region.
+func (r GridRegion) Tiles() []GridRegion {
+	dimensions := r.Grid.access.Layer().Dimensions
+	tileWidth, tileHeight := dimensions[0].TileSize, dimensions[1].TileSize
+	parts := []GridRegion{}
+	for y := r.Y; y < r.Y+r.Height; y = (y/tileHeight + 1) * tileHeight {
+		height := min(r.Y+r.Height, (y/tileHeight+1)*tileHeight) - y
+		for x := r.X; x < r.X+r.Width; {
+			// columns continuing past the antimeridian start again at the first tile
+			wrapped := r.Grid.wrapX(x)
+			width := min(r.X+r.Width-x, (wrapped/tileWidth+1)*tileWidth-wrapped, r.Grid.width-wrapped)
+			parts = append(parts, GridRegion{Grid: r.Grid, X: x, Y: y, Width: width, Height: height})
+			x += width
+		}
+	}
+	return parts
+}
+
+// EachTile reads the samples of each tile part of the region returned by Tiles in a single pass, on as many
+// goroutines as there are processors when workers is 0, and calls fn with the index of the goroutine, the part
+// and its samples in row-major order. Calls from the same goroutine never overlap, so fn can accumulate into state
+// kept per goroutine without locking. The first error stops the pass.
+func (r GridRegion) EachTile(workers int, fn func(worker int, part GridRegion, samples []GebcoSample) error) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	parts := make(chan GridRegion)
+	errs := make([]error, workers)
+	done := make(chan struct{})
+	var stop sync.Once
+	wg := sync.WaitGroup{}
+	for worker := range workers {
+		wg.Go(func() {
+			for part := range parts {
+				samples, err := part.ReadSamples()
+				if err == nil {
+					err = fn(worker, part, samples)
+				}
+				if err != nil {
+					errs[worker] = err
+					stop.Do(func() { close(done) })
+					return
+				}
+			}
+		})
+	}
+feed:
+	for _, part := range r.Tiles() {
+		select {
+		case parts <- part:
+		case <-done:
+			break feed
+		}
+	}
+	close(parts)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package gebco
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ColorStop assigns a colour to an elevation in a ColorRamp.
+type ColorStop struct {
+	Value float64
+	Color color.NRGBA
+}
+
+// ColorRamp maps elevations to colours by linear interpolation between stops sorted by increasing value.
+// Elevations outside the ramp take the colour of the nearest end.
+type ColorRamp []ColorStop
+
+// Color returns the colour of an elevation.
+func (r ColorRamp) Color(value float64) color.NRGBA {
+	if len(r) == 0 {
+		return color.NRGBA{}
+	}
+	index, _ := slices.BinarySearchFunc(r, value, func(stop ColorStop, v float64) int {
+		if stop.Value < v {
+			return -1
+		} else if stop.Value > v {
+			return 1
+		}
+		return 0
+	})
+	if index == 0 {
+		return r[0].Color
+	}
+	if index == len(r) {
+		return r[len(r)-1].Color
+	}
+	low, high := r[index-1], r[index]
+	return lerpColor(low.Color, high.Color, (value-low.Value)/(high.Value-low.Value))
+}
+
+func lerpColor(a, b color.NRGBA, t float64) color.NRGBA {
+	lerp := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + (float64(y)-float64(x))*t))
+	}
+	return color.NRGBA{R: lerp(a.R, b.R), G: lerp(a.G, b.G), B: lerp(a.B, b.B), A: lerp(a.A, b.A)}
+}
+
+// GebcoRamp is a hypsometric and bathymetric colour ramp in the style of the GEBCO printed charts, running from
+// deep violet-blue abyssal plains through pale shelf blues to green lowlands and brown and white mountains.
+var GebcoRamp = ColorRamp{
+	{Value: -11000, Color: color.NRGBA{R: 0x14, G: 0x0a, B: 0x4a, A: 0xff}},
+	{Value: -8000, Color: color.NRGBA{R: 0x1f, G: 0x1f, B: 0x7a, A: 0xff}},
+	{Value: -6000, Color: color.NRGBA{R: 0x26, G: 0x40, B: 0x9c, A: 0xff}},
+	{Value: -4000, Color: color.NRGBA{R: 0x2f, G: 0x6b, B: 0xb8, A: 0xff}},
+	{Value: -2000, Color: color.NRGBA{R: 0x4f, G: 0x9b, B: 0xd1, A: 0xff}},
+	{Value: -200, Color: color.NRGBA{R: 0x9c, G: 0xd3, B: 0xea, A: 0xff}},
+	{Value: -1, Color: color.NRGBA{R: 0xd2, G: 0xef, B: 0xf7, A: 0xff}},
+	{Value: 0, Color: color.NRGBA{R: 0x3c, G: 0x8c, B: 0x4a, A: 0xff}},
+	{Value: 200, Color: color.NRGBA{R: 0x8c, G: 0xbf, B: 0x6b, A: 0xff}},
+	{Value: 600, Color: color.NRGBA{R: 0xe6, G: 0xdc, B: 0x8c, A: 0xff}},
+	{Value: 1500, Color: color.NRGBA{R: 0xc8, G: 0x96, B: 0x5a, A: 0xff}},
+	{Value: 3000, Color: color.NRGBA{R: 0x96, G: 0x64, B: 0x46, A: 0xff}},
+	{Value: 5000, Color: color.NRGBA{R: 0xdc, G: 0xdc, B: 0xdc, A: 0xff}},
+	{Value: 8800, Color: color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}},
+}
+
+// ParseHexColor parses a colour written as #rrggbb or #rrggbbaa.
+func ParseHexColor(s string) (color.NRGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return color.NRGBA{}, fmt.Errorf("invalid colour %q: expected #rrggbb or #rrggbbaa", s)
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("invalid colour %q: %w", s, err)
+	}
+	if len(hex) == 6 {
+		value = value<<8 | 0xff
+	}
+	return color.NRGBA{R: uint8(value >> 24), G: uint8(value >> 16), B: uint8(value >> 8), A: uint8(value)}, nil
+}
+
+// LandMode controls how land pixels are drawn by Render.
+type LandMode int
+
+const (
+	LandColored     LandMode = iota // Land is coloured by the palette like any other value.
+	LandTransparent                 // Land is left fully transparent.
+	LandMasked                      // Land is filled with a single mask colour.
+)
+
+// RenderOptions configures Render.
+type RenderOptions struct {
+	Surface      GebcoDataType // The values to colour: the ice or sub-ice surface, or type IDs.
+	Palette      Palette       // The colours of the values, GebcoRamp for surfaces and TidPalette for type IDs when nil.
+	Hillshade    bool          // Whether to shade the colours with a multidirectional hillshade (of the ice surface when colouring type IDs).
+	Exaggeration float64       // Vertical exaggeration applied to the hillshade, 1 when zero.
+	Land         LandMode      // How land pixels are drawn.
+	MaskColor    color.NRGBA   // The colour of land pixels when Land is LandMasked.
+}
+
+// Render draws a region of a grid into a width by height image in plate carrée, sampling the nearest grid pixel
+// for each image pixel. Choose the grid with Dataset.GridFor so that the grid is not much finer than the image.
+func Render(grid *Grid, bounds Bounds, width, height int, opts RenderOptions) (*image.NRGBA, error) {
+	degreesPerPixelX := bounds.Width() / float64(width)
+	degreesPerPixelY := bounds.Height() / float64(height)
+	projection := imageProjection{
+		position: func(i, j float64) LatLng {
+			return bounds.At(i/float64(width), j/float64(height))
+		},
+		spacing: func(j int) (dx, dy float64) {
+			lat := bounds.North - (float64(j)+0.5)*degreesPerPixelY
+			dx, _ = CellSize(lat, degreesPerPixelX)
+			_, dy = CellSize(lat, degreesPerPixelY)
+			return dx, dy
+		},
+	}
+	return renderProjected(grid, width, height, projection, opts)
+}
+
+// imageProjection places the pixels of a rendered image on the globe.
+type imageProjection struct {
+	position func(i, j float64) LatLng    // The position of a continuous image coordinate, pixel centres at +0.5.
+	spacing  func(j int) (dx, dy float64) // The metric spacing between the pixel centres of an image row.
+}
+
+// renderProjected draws a grid into an image whose pixels are placed by an arbitrary projection.
+func renderProjected(grid *Grid, width, height int, projection imageProjection, opts RenderOptions) (*image.NRGBA, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid render size %dx%d", width, height)
+	}
+	palette := opts.Palette
+	if palette == nil {
+		if opts.Surface == GebcoDataTypeId {
+			palette = TidPalette
+		} else {
+			palette = GebcoRamp
+		}
+	}
+	shadeSurface := opts.Surface
+	if opts.Surface == GebcoDataTypeId {
+		if !grid.HasTid() {
+			return nil, fmt.Errorf("layer %s has no type ID channel to render", grid.Name())
+		}
+		shadeSurface = GebcoDataIce
+	}
+	exaggeration := opts.Exaggeration
+	if exaggeration == 0 {
+		exaggeration = 1
+	}
+
+	// read a one pixel border around the image so the hillshade has complete neighbourhoods at the edges
+	stride := width + 2
+	samples := make([]GebcoSample, stride*(height+2))
+	buf := grid.newSampleBuffer()
+	for j := -1; j <= height; j++ {
+		for i := -1; i <= width; i++ {
+			position := projection.position(float64(i)+0.5, float64(j)+0.5)
+			position.Lat = max(-90, min(90, position.Lat))
+			x, y := grid.Pixel(position)
+			sample, err := grid.sampleInto(x, y, buf)
+			if err != nil {
+				return nil, err
+			}
+			samples[(j+1)*stride+i+1] = sample
+		}
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for j := range height {
+		dx, dy := projection.spacing(j)
+		for i := range width {
+			sample := samples[(j+1)*stride+i+1]
+			land := sample.IsLand()
+			if land && opts.Land == LandTransparent {
+				continue
+			}
+			if land && opts.Land == LandMasked {
+				img.SetNRGBA(i, j, opts.MaskColor)
+				continue
+			}
+
+			pixel := palette.Color(sample.Value(opts.Surface))
+			if opts.Hillshade {
+				var window [9]float64
+				for wy := range 3 {
+					for wx := range 3 {
+						window[wy*3+wx] = samples[(j+wy)*stride+i+wx].Value(shadeSurface) * exaggeration
+					}
+				}
+				pixel = shade(pixel, multidirectionalHillshade(TerrainFromWindow(window, dx, dy)))
+			}
+			img.SetNRGBA(i, j, pixel)
+		}
+	}
+
+	return img, nil
+}
+
+const hillshadeAltitude = 45.0 // The altitude of the light sources in degrees above the horizon.
+
+// multidirectionalHillshade returns the illumination of a surface lit from the north-west quadrant by four
+// light sources, each weighted by how directly the surface faces away from it, so that slopes of every
+// aspect keep their relief. Flat ground has an illumination of one.
+func multidirectionalHillshade(terrain Terrain) float64 {
+	zenith := radians(90 - hillshadeAltitude)
+	if terrain.Aspect < 0 {
+		return 1
+	}
+	slope := radians(terrain.Slope)
+	aspect := radians(terrain.Aspect)
+
+	var shaded, weights float64
+	for _, azimuth := range []float64{225, 270, 315, 360} {
+		az := radians(azimuth)
+		weight := (1 - math.Cos(aspect-az)) / 2
+		illumination := math.Cos(zenith)*math.Cos(slope) + math.Sin(zenith)*math.Sin(slope)*math.Cos(az-aspect)
+		shaded += weight * max(0, illumination)
+		weights += weight
+	}
+	if weights == 0 {
+		return math.Cos(slope)
+	}
+	return (shaded / weights) / math.Cos(zenith)
+}
+
+// shade darkens or lightens a colour by an illumination factor, where one leaves the colour unchanged.
+func shade(c color.NRGBA, illumination float64) color.NRGBA {
+	factor := 0.5 + 0.5*illumination
+	scale := func(v uint8) uint8 {
+		return uint8(max(0, min(255, math.Round(float64(v)*factor))))
+	}
+	return color.NRGBA{R: scale(c.R), G: scale(c.G), B: scale(c.B), A: c.A}
+}
@@ -0,0 +1,65 @@
+package gebco
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestColorRamp(t *testing.T) {
+	ramp := ColorRamp{
+		{Value: -100, Color: color.NRGBA{R: 0, G: 0, B: 200, A: 255}},
+		{Value: 0, Color: color.NRGBA{R: 100, G: 0, B: 0, A: 255}},
+	}
+	cases := []struct {
+		name     string
+		value    float64
+		expected color.NRGBA
+	}{
+		{"below ramp", -500, color.NRGBA{R: 0, G: 0, B: 200, A: 255}},
+		{"midpoint", -50, color.NRGBA{R: 50, G: 0, B: 100, A: 255}},
+		{"exact stop", 0, color.NRGBA{R: 100, G: 0, B: 0, A: 255}},
+		{"above ramp", 500, color.NRGBA{R: 100, G: 0, B: 0, A: 255}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := ramp.Color(c.value); actual != c.expected {
+				t.Errorf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRenderLandModes(t *testing.T) {
+	// western hemisphere is ocean, eastern hemisphere is land
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		if x < 18 {
+			return GebcoSample{Ice: -4000, SubIce: -4000, Tid: GebcoTypeMultiBeam}
+		}
+		return GebcoSample{Ice: 100, SubIce: 100, Tid: GebcoTypeLand}
+	})
+	mask := color.NRGBA{R: 1, G: 2, B: 3, A: 255}
+
+	cases := []struct {
+		name string
+		land LandMode
+		east color.NRGBA
+	}{
+		{"colored", LandColored, GebcoRamp.Color(100)},
+		{"transparent", LandTransparent, color.NRGBA{}},
+		{"masked", LandMasked, mask},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			img, err := Render(dataset.Grid(), GlobalBounds, 4, 2, RenderOptions{Surface: GebcoDataIce, Hillshade: true, Land: c.land, MaskColor: mask})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if west := img.NRGBAAt(0, 0); west != GebcoRamp.Color(-4000) {
+				t.Errorf("expected flat ocean to be unshaded ramp colour, got %v", west)
+			}
+			if east := img.NRGBAAt(3, 1); east != c.east {
+				t.Errorf("expected land colour %v, got %v", c.east, east)
+			}
+		})
+	}
+}
@@ -0,0 +1,494 @@
+package gebco
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Annulus is a ring around a pixel between two geodesic distances in metres. Pixels whose centres lie within
+// the ring, inclusive of both radii, belong to it.
+type Annulus struct {
+	Inner float64 `json:"inner"`
+	Outer float64 `json:"outer"`
+}
+
+// ParseAnnuli parses a list of annuli separated by commas, each written as "inner:outer" in metres, e.g.
+// "0:1000,5000:25000".
+func ParseAnnuli(text string) ([]Annulus, error) {
+	annuli := []Annulus{}
+	for part := range strings.SplitSeq(text, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		inner, outer, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid annulus %q: expected inner:outer", part)
+		}
+		innerRadius, err := strconv.ParseFloat(strings.TrimSpace(inner), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid inner radius in %q: %w", part, err)
+		}
+		outerRadius, err := strconv.ParseFloat(strings.TrimSpace(outer), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid outer radius in %q: %w", part, err)
+		}
+		annuli = append(annuli, Annulus{Inner: innerRadius, Outer: outerRadius})
+	}
+	return annuli, nil
+}
+
+// RoughnessOptions configures a RoughnessKernel.
+type RoughnessOptions struct {
+	Surface   GebcoDataType // The surface (ice, sub-ice) to analyse.
+	VrmRadius float64       // The radius in metres of the neighbourhood the vector ruggedness measure is computed over.
+	Bpi       []Annulus     // The annuli to compute a bathymetric position index over, one per scale.
+}
+
+// Roughness holds the terrain roughness and bathymetric position indices of a pixel.
+type Roughness struct {
+	// Rugosity is the ratio of the surface area of the triangles joining the pixel to its eight neighbours to
+	// their planar area, after Jenness (2004), 1 on flat ground.
+	Rugosity float64 `json:"rugosity"`
+	// Tri is the terrain ruggedness index, the mean absolute difference in metres between the pixel and its eight
+	// neighbours, after Wilson et al. (2007).
+	Tri float64 `json:"tri"`
+	// Vrm is the vector ruggedness measure of Sappington et al. (2007), the dispersion of the unit normals of the
+	// pixels in its neighbourhood, from 0 on a plane to 1 on the most rugged terrain.
+	Vrm float64 `json:"vrm"`
+	// Bpi is the bathymetric position index of Lundblad et al. (2006) at each scale, the elevation in metres of
+	// the pixel above the mean of the pixels in the annulus, positive on crests and negative in depressions. It is
+	// NaN where an annulus holds no pixels.
+	Bpi []float64 `json:"bpi"`
+}
+
+// Values returns the roughness of a pixel flattened in the order of BandNames.
+func (r Roughness) Values() []float64 {
+	return append([]float64{r.Rugosity, r.Tri, r.Vrm}, r.Bpi...)
+}
+
+// ringRange is the part of a neighbourhood in the row dy rows from a pixel: the pixels whose offset in columns has a
+// magnitude above inner and at most outer, where -1 is none.
+type ringRange struct {
+	dy, inner, outer int
+}
+
+// RoughnessKernel computes roughness over a grid with neighbourhoods in metres, resolving them into the pixels
+// whose centres lie within geodesic distance of each row, so that they widen in longitude towards the poles until
+// they are clamped to whole rows. Neighbourhoods wrap around the antimeridian and are cut at the poles, and a VRM
+// radius below the pixel spacing leaves only the pixel itself. Each row of a neighbourhood is a run of columns, so
+// the sums over it come from running sums along the rows at a cost independent of its width. It keeps the runs of
+// the rows of the last region it computed and is not safe for concurrent use.
+type RoughnessKernel struct {
+	grid   *Grid
+	opts   RoughnessOptions
+	ranges map[int][][]ringRange // For each row, the runs of the VRM neighbourhood and then of each annulus.
+}
+
+// NewRoughnessKernel creates a kernel computing roughness over a grid.
+func NewRoughnessKernel(grid *Grid, opts RoughnessOptions) (*RoughnessKernel, error) {
+	if opts.Surface == GebcoDataTypeId {
+		return nil, fmt.Errorf("cannot compute roughness of type IDs")
+	}
+	// beyond half way around the globe a neighbourhood would hold every pixel
+	maxRadius := math.Pi * EarthRadius
+	if opts.VrmRadius <= 0 || opts.VrmRadius > maxRadius {
+		return nil, fmt.Errorf("invalid VRM radius %g", opts.VrmRadius)
+	}
+	for _, annulus := range opts.Bpi {
+		if annulus.Inner < 0 || annulus.Outer <= annulus.Inner || annulus.Outer > maxRadius {
+			return nil, fmt.Errorf("invalid BPI annulus %g:%g", annulus.Inner, annulus.Outer)
+		}
+	}
+	return &RoughnessKernel{grid: grid, opts: opts, ranges: map[int][][]ringRange{}}, nil
+}
+
+// BandNames returns the names of the roughness values in the order Values returns them: rugosity, tri, vrm and a
+// bpi band for each annulus named after its radii, e.g. "bpi-1000-5000".
+func (k *RoughnessKernel) BandNames() []string {
+	names := []string{"rugosity", "tri", "vrm"}
+	for _, annulus := range k.opts.Bpi {
+		names = append(names, fmt.Sprintf("bpi-%g-%g", annulus.Inner, annulus.Outer))
+	}
+	return names
+}
+
+// halfWidth returns the greatest offset in columns, up to half way around the globe, of the pixels of a row within
+// a distance of the first pixel of another row (or strictly within it), or -1 if there are none. Distances grow
+// with the offset, so it is found by bisection.
+func (k *RoughnessKernel) halfWidth(y, row int, radius float64, strict bool) int {
+	center := k.grid.PixelCenter(0, y)
+	within := func(dx int) bool {
+		distance := Distance(center, k.grid.PixelCenter(dx, row))
+		return distance < radius || (!strict && distance == radius)
+	}
+	if !within(0) {
+		return -1
+	}
+	low, high := 0, k.grid.Width()/2
+	for low < high {
+		mid := (low + high + 1) / 2
+		if within(mid) {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	return low
+}
+
+// rowRanges returns the runs of the pixels within the VRM radius and within each annulus of a row.
+func (k *RoughnessKernel) rowRanges(y int) [][]ringRange {
+	if ranges, ok := k.ranges[y]; ok {
+		return ranges
+	}
+	maxRadius := k.opts.VrmRadius
+	for _, annulus := range k.opts.Bpi {
+		maxRadius = max(maxRadius, annulus.Outer)
+	}
+	ranges := make([][]ringRange, 1+len(k.opts.Bpi))
+	maxDy := int(maxRadius / k.grid.PixelSpacing())
+	for dy := -maxDy; dy <= maxDy; dy++ {
+		row := y + dy
+		if row < 0 || row >= k.grid.Height() {
+			continue
+		}
+		if outer := k.halfWidth(y, row, k.opts.VrmRadius, false); outer >= 0 {
+			ranges[0] = append(ranges[0], ringRange{dy: dy, inner: -1, outer: outer})
+		}
+		for i, annulus := range k.opts.Bpi {
+			inner, outer := k.halfWidth(y, row, annulus.Inner, true), k.halfWidth(y, row, annulus.Outer, false)
+			if outer > inner {
+				ranges[i+1] = append(ranges[i+1], ringRange{dy: dy, inner: inner, outer: outer})
+			}
+		}
+	}
+	k.ranges[y] = ranges
+	return ranges
+}
+
+// rowSums holds the running sums along a row of the columns read for a region, for the sums over runs of it.
+type rowSums struct {
+	sums  []float64 // sums[c] is the sum of the first c columns.
+	wraps bool      // Whether the columns are the whole row, so that runs wrap around it.
+}
+
+// run returns the sum and the number of the columns within an offset of a column, wrapping around a whole row and
+// clamped to it.
+func (r rowSums) run(column, offset int) (float64, int) {
+	width := len(r.sums) - 1
+	if offset < 0 {
+		return 0, 0
+	}
+	if r.wraps && 2*offset+1 >= width {
+		return r.sums[width], width
+	}
+	low, high := column-offset, column+offset+1
+	switch {
+	case low < 0:
+		return r.sums[high] + r.sums[width] - r.sums[low+width], 2*offset + 1
+	case high > width:
+		return r.sums[width] - r.sums[low] + r.sums[high-width], 2*offset + 1
+	}
+	return r.sums[high] - r.sums[low], 2*offset + 1
+}
+
+// ring returns the sum and the number of the columns of a run of a neighbourhood around a column.
+func (r rowSums) ring(column int, rr ringRange) (float64, int) {
+	outerSum, outerCount := r.run(column, rr.outer)
+	innerSum, innerCount := r.run(column, rr.inner)
+	return outerSum - innerSum, outerCount - innerCount
+}
+
+func newRowSums(values []float64, wraps bool) rowSums {
+	sums := make([]float64, len(values)+1)
+	for c, value := range values {
+		sums[c+1] = sums[c] + value
+	}
+	return rowSums{sums: sums, wraps: wraps}
+}
+
+// Raster computes the roughness of every pixel of a region into a raster in plate carrée, with the bands named by
+// BandNames.
+func (k *RoughnessKernel) Raster(region GridRegion) (*Raster, error) {
+	step := k.grid.DegreesPerPixel()
+	raster := NewRaster(PlateCarree{}, region.West(), region.North(), step, step, region.Width, region.Height, k.BandNames()...)
+	err := k.each(region, func(i, j int, roughness Roughness) {
+		for band, value := range roughness.Values() {
+			raster.Bands[band][region.Index(i, j)] = float32(value)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return raster, nil
+}
+
+// At computes the roughness of a pixel.
+func (k *RoughnessKernel) At(x, y int) (Roughness, error) {
+	var roughness Roughness
+	err := k.each(GridRegion{Grid: k.grid, X: k.grid.wrapX(x), Y: y, Width: 1, Height: 1}, func(_, _ int, r Roughness) {
+		roughness = r
+	})
+	return roughness, err
+}
+
+// each computes the roughness of every pixel of a region row by row, calling fn with its position in the region.
+// The region is split into bands of rows whose neighbourhoods have widths within a factor of two, so that the rows
+// near the poles do not widen the columns read for the rest.
+func (k *RoughnessKernel) each(region GridRegion, fn func(i, j int, roughness Roughness)) error {
+	for y := range k.ranges {
+		if y < region.Y || y >= region.Y+region.Height {
+			delete(k.ranges, y)
+		}
+	}
+	widths := make([]int, region.Height)
+	for j := range widths {
+		pad := 0
+		for _, ranges := range k.rowRanges(region.Y + j) {
+			for _, rr := range ranges {
+				pad = max(pad, rr.outer)
+			}
+		}
+		widths[j] = bits.Len(uint(pad))
+	}
+	for start := 0; start < region.Height; {
+		end := start + 1
+		for end < region.Height && widths[end] == widths[start] {
+			end++
+		}
+		band := GridRegion{Grid: region.Grid, X: region.X, Y: region.Y + start, Width: region.Width, Height: end - start}
+		err := k.eachInBand(band, func(i, j int, roughness Roughness) {
+			fn(i, start+j, roughness)
+		})
+		if err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+// eachInBand computes the roughness of every pixel of a band of rows. The rows of the surface are read once each,
+// padded by the widest neighbourhood of the band, and only the rows its neighbourhoods reach are held at a time.
+func (k *RoughnessKernel) eachInBand(region GridRegion, fn func(i, j int, roughness Roughness)) error {
+	grid := k.grid
+	pad, maxDy, maxVrmDy := 0, 0, 0
+	for j := range region.Height {
+		for ring, ranges := range k.rowRanges(region.Y + j) {
+			for _, rr := range ranges {
+				pad, maxDy = max(pad, rr.outer), max(maxDy, rr.dy)
+				if ring == 0 {
+					maxVrmDy = max(maxVrmDy, rr.dy)
+				}
+			}
+		}
+	}
+
+	// read the columns of the region padded for the neighbourhoods and the windows, or whole rows once they cover
+	// the globe
+	x0, width := region.X-pad-1, region.Width+2*pad+2
+	wraps := width >= grid.Width()
+	if wraps {
+		x0, width = 0, grid.Width()
+	}
+	column := func(x int) int {
+		if wraps {
+			return grid.wrapX(x)
+		}
+		return x - x0
+	}
+
+	elevations := map[int][]float32{}
+	elevationRow := func(y int) ([]float32, error) {
+		if row, ok := elevations[y]; ok {
+			return row, nil
+		}
+		row, err := GridRegion{Grid: grid, X: grid.wrapX(x0), Y: y, Width: width, Height: 1}.ReadValues(k.opts.Surface)
+		if err != nil {
+			return nil, err
+		}
+		elevations[y] = row
+		return row, nil
+	}
+	// window reads the window of a column of a row, continuing over the poles as Grid.Window does
+	buf := grid.newSampleBuffer()
+	window := func(c, y int) ([9]float64, error) {
+		var w [9]float64
+		for dy := -1; dy <= 1; dy++ {
+			if y+dy < 0 || y+dy >= grid.Height() {
+				for dx := -1; dx <= 1; dx++ {
+					nx, ny := grid.acrossPole(x0+c+dx, y+dy)
+					sample, err := grid.sampleInto(nx, ny, buf)
+					if err != nil {
+						return w, err
+					}
+					w[(dy+1)*3+dx+1] = sample.Value(k.opts.Surface)
+				}
+				continue
+			}
+			row, err := elevationRow(y + dy)
+			if err != nil {
+				return w, err
+			}
+			for dx := -1; dx <= 1; dx++ {
+				w[(dy+1)*3+dx+1] = float64(row[(c+dx+width)%width])
+			}
+		}
+		return w, nil
+	}
+	sums := map[int]rowSums{}
+	sumRow := func(y int) (rowSums, error) {
+		if row, ok := sums[y]; ok {
+			return row, nil
+		}
+		row, err := elevationRow(y)
+		if err != nil {
+			return rowSums{}, err
+		}
+		values := make([]float64, width)
+		for c, value := range row {
+			values[c] = float64(value)
+		}
+		sums[y] = newRowSums(values, wraps)
+		return sums[y], nil
+	}
+	// the unit normals of a row, left zero in the outer columns of a padded row which have no window
+	normals := map[int][3]rowSums{}
+	normalRow := func(y int) ([3]rowSums, error) {
+		if row, ok := normals[y]; ok {
+			return row, nil
+		}
+		dx, dy := CellSize(grid.PixelCenter(0, y).Lat, grid.DegreesPerPixel())
+		var values [3][]float64
+		for n := range values {
+			values[n] = make([]float64, width)
+		}
+		for c := range width {
+			if !wraps && (c == 0 || c == width-1) {
+				continue
+			}
+			w, err := window(c, y)
+			if err != nil {
+				return [3]rowSums{}, err
+			}
+			terrain := TerrainFromWindow(w, dx, dy)
+			slope, aspect := radians(terrain.Slope), radians(max(terrain.Aspect, 0))
+			values[0][c] = math.Sin(slope) * math.Sin(aspect)
+			values[1][c] = math.Sin(slope) * math.Cos(aspect)
+			values[2][c] = math.Cos(slope)
+		}
+		row := [3]rowSums{newRowSums(values[0], wraps), newRowSums(values[1], wraps), newRowSums(values[2], wraps)}
+		normals[y] = row
+		return row, nil
+	}
+
+	step := grid.DegreesPerPixel()
+	for j := range region.Height {
+		y := region.Y + j
+		// drop the rows no neighbourhood of this row or those below it reaches
+		for row := range elevations {
+			if row < y-maxDy-1 {
+				delete(elevations, row)
+			}
+		}
+		for row := range sums {
+			if row < y-maxDy {
+				delete(sums, row)
+			}
+		}
+		for row := range normals {
+			if row < y-maxVrmDy {
+				delete(normals, row)
+			}
+		}
+
+		ranges := k.rowRanges(y)
+		dx, dy := CellSize(grid.PixelCenter(0, y).Lat, step)
+		for i := range region.Width {
+			c := column(region.X + i)
+			w, err := window(c, y)
+			if err != nil {
+				return err
+			}
+			roughness := Roughness{Rugosity: RugosityFromWindow(w, dx, dy), Tri: TriFromWindow(w), Bpi: make([]float64, len(k.opts.Bpi))}
+
+			var n [3]float64
+			count := 0
+			for _, rr := range ranges[0] {
+				row, err := normalRow(y + rr.dy)
+				if err != nil {
+					return err
+				}
+				for axis := range n {
+					sum, ringCount := row[axis].ring(c, rr)
+					n[axis] += sum
+					if axis == 0 {
+						count += ringCount
+					}
+				}
+			}
+			roughness.Vrm = 1 - math.Sqrt(n[0]*n[0]+n[1]*n[1]+n[2]*n[2])/float64(count)
+
+			for b, ring := range ranges[1:] {
+				sum, count := 0.0, 0
+				for _, rr := range ring {
+					row, err := sumRow(y + rr.dy)
+					if err != nil {
+						return err
+					}
+					ringSum, ringCount := row.ring(c, rr)
+					sum, count = sum+ringSum, count+ringCount
+				}
+				roughness.Bpi[b] = w[4] - sum/float64(count)
+			}
+			fn(i, j, roughness)
+		}
+	}
+	return nil
+}
+
+// RugosityFromWindow computes the rugosity of the centre of a 3x3 window of elevations in the order returned by
+// Grid.Window, with dx and dy the east-west and north-south pixel spacing in metres.
+func RugosityFromWindow(window [9]float64, dx, dy float64) float64 {
+	// the neighbours in order around the centre, starting north
+	ring := [8][3]float64{
+		{0, dy, window[1]}, {dx, dy, window[2]}, {dx, 0, window[5]}, {dx, -dy, window[8]},
+		{0, -dy, window[7]}, {-dx, -dy, window[6]}, {-dx, 0, window[3]}, {-dx, dy, window[0]},
+	}
+	surface := 0.0
+	for i, a := range ring {
+		b := ring[(i+1)%8]
+		ax, ay, az := a[0], a[1], a[2]-window[4]
+		bx, by, bz := b[0], b[1], b[2]-window[4]
+		surface += math.Sqrt(math.Pow(ay*bz-az*by, 2)+math.Pow(az*bx-ax*bz, 2)+math.Pow(ax*by-ay*bx, 2)) / 2
+	}
+	return surface / (4 * dx * dy)
+}
+
+// TriFromWindow computes the terrain ruggedness index of the centre of a 3x3 window of elevations.
+func TriFromWindow(window [9]float64) float64 {
+	sum := 0.0
+	for i, z := range window {
+		if i != 4 {
+			sum += math.Abs(z - window[4])
+		}
+	}
+	return sum / 8
+}
+
+// RoughnessRaster computes the roughness of every pixel of a region into a raster in plate carrée, with the
+// bands named by BandNames.
+func RoughnessRaster(grid *Grid, region Bounds, opts RoughnessOptions) (*Raster, error) {
+	kernel, err := NewRoughnessKernel(grid, opts)
+	if err != nil {
+		return nil, err
+	}
+	pixels, err := grid.Region(region)
+	if err != nil {
+		return nil, err
+	}
+	return kernel.Raster(pixels)
+}
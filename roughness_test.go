@@ -0,0 +1,181 @@
+package gebco
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestRugosityAndTriFromWindow(t *testing.T) {
+	cases := []struct {
+		name             string
+		window           [9]float64
+		expectedRugosity float64
+		expectedTri      float64
+	}{
+		{"flat", [9]float64{5, 5, 5, 5, 5, 5, 5, 5, 5}, 1, 0},
+		{"rising to the east", [9]float64{0, 1, 2, 0, 1, 2, 0, 1, 2}, math.Sqrt2, 0.75},
+		{"pit", [9]float64{1, 1, 1, 1, 0, 1, 1, 1, 1}, 0, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.expectedRugosity != 0 {
+				if rugosity := RugosityFromWindow(c.window, 1, 1); math.Abs(rugosity-c.expectedRugosity) > 1e-9 {
+					t.Errorf("expected rugosity %f, got %f", c.expectedRugosity, rugosity)
+				}
+			} else if rugosity := RugosityFromWindow(c.window, 1, 1); rugosity <= 1 {
+				t.Errorf("expected rugosity above 1, got %f", rugosity)
+			}
+			if tri := TriFromWindow(c.window); math.Abs(tri-c.expectedTri) > 1e-9 {
+				t.Errorf("expected TRI %f, got %f", c.expectedTri, tri)
+			}
+		})
+	}
+}
+
+func TestParseAnnuli(t *testing.T) {
+	annuli, err := ParseAnnuli("0:1000, 5000:25000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(annuli, []Annulus{{0, 1000}, {5000, 25000}}) {
+		t.Errorf("unexpected annuli %v", annuli)
+	}
+	if _, err := ParseAnnuli("1000"); err == nil {
+		t.Errorf("expected an error for an annulus without an outer radius")
+	}
+}
+
+func TestRoughnessKernel(t *testing.T) {
+	// a flat seafloor of 10 degree pixels with a single raised pixel just north of the equator
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		if x == 18 && y == 8 {
+			return GebcoSample{Ice: 0, SubIce: 0, Tid: GebcoTypeMultiBeam}
+		}
+		return GebcoSample{Ice: -1000, SubIce: -1000, Tid: GebcoTypeMultiBeam}
+	})
+	grid := dataset.Grid()
+	opts := RoughnessOptions{Surface: GebcoDataSubIce, VrmRadius: 1200e3, Bpi: []Annulus{{0, 1200e3}, {1200e3, 2300e3}}}
+	kernel, err := NewRoughnessKernel(grid, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewRoughnessKernel(grid, RoughnessOptions{Surface: GebcoDataSubIce, VrmRadius: 1000, Bpi: []Annulus{{0, 30000e3}}}); err == nil {
+		t.Errorf("expected an error for an annulus wider than the globe")
+	}
+
+	// the inner annulus holds the pixel and its four edge neighbours, the outer one the rest of the 5x5 block
+	raised, err := kernel.At(18, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(raised.Bpi[0]-800) > 1e-9 || math.Abs(raised.Bpi[1]-1000) > 1e-9 {
+		t.Errorf("expected BPIs of 800 and 1000, got %v", raised.Bpi)
+	}
+	if raised.Tri != 1000 || raised.Rugosity <= 1 || raised.Vrm <= 0 {
+		t.Errorf("unexpected roughness of the raised pixel %+v", raised)
+	}
+	flat, err := kernel.At(5, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flat.Tri != 0 || flat.Rugosity != 1 || math.Abs(flat.Vrm) > 1e-9 || flat.Bpi[0] != 0 || flat.Bpi[1] != 0 {
+		t.Errorf("expected no roughness on the flat seafloor, got %+v", flat)
+	}
+
+	// the neighbourhoods widen towards the pole until they span the whole polar row
+	pixels := func(y int) int {
+		count := 0
+		for _, rr := range kernel.rowRanges(y)[1] {
+			count += min(2*rr.outer+1, 36) - max(min(2*rr.inner+1, 36), 0)
+		}
+		return count
+	}
+	if equator, pole := pixels(8), pixels(0); equator != 5 || pole <= 36 {
+		t.Errorf("expected 5 pixels in the equatorial annulus and more than a whole row at the pole, got %d and %d", equator, pole)
+	}
+
+	raster, err := RoughnessRaster(grid, Bounds{West: -10, South: -20, East: 20, North: 20}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(raster.BandNames, []string{"rugosity", "tri", "vrm", "bpi-0-1.2e+06", "bpi-1.2e+06-2.3e+06"}) {
+		t.Errorf("unexpected band names %v", raster.BandNames)
+	}
+	if raster.Width != 3 || raster.Height != 4 || raster.Bands[1][raster.Width+1] != 1000 {
+		t.Errorf("expected the raised pixel in a 3x4 raster, got %dx%d with TRI %v", raster.Width, raster.Height, raster.Bands[1])
+	}
+}
+
+func TestRoughnessRasterMatchesNeighbourhoods(t *testing.T) {
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		z := int16((x*37+y*91)%200 - 1000)
+		return GebcoSample{Ice: z, SubIce: z, Tid: GebcoTypeMultiBeam}
+	})
+	grid := dataset.Grid()
+	opts := RoughnessOptions{Surface: GebcoDataIce, VrmRadius: 1200e3, Bpi: []Annulus{{0, 1200e3}, {1200e3, 3000e3}}}
+
+	// the roughness of a pixel summed over every pixel of its neighbourhoods in turn
+	expected := func(x, y int) []float64 {
+		center := grid.PixelCenter(x, y)
+		var nx, ny, nz float64
+		vrmCount := 0
+		sums, counts := make([]float64, len(opts.Bpi)), make([]int, len(opts.Bpi))
+		for row := range grid.Height() {
+			for dx := range grid.Width() {
+				distance := Distance(center, grid.PixelCenter(x+dx, row))
+				if distance <= opts.VrmRadius {
+					terrain, err := grid.Terrain(grid.wrapX(x+dx), row, opts.Surface)
+					if err != nil {
+						t.Fatal(err)
+					}
+					slope, aspect := radians(terrain.Slope), radians(max(terrain.Aspect, 0))
+					nx, ny, nz = nx+math.Sin(slope)*math.Sin(aspect), ny+math.Sin(slope)*math.Cos(aspect), nz+math.Cos(slope)
+					vrmCount++
+				}
+				for i, annulus := range opts.Bpi {
+					if distance >= annulus.Inner && distance <= annulus.Outer {
+						sample, err := grid.Sample(x+dx, row)
+						if err != nil {
+							t.Fatal(err)
+						}
+						sums[i] += sample.Value(opts.Surface)
+						counts[i]++
+					}
+				}
+			}
+		}
+		window, err := grid.Window(x, y, opts.Surface)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dx, dy := CellSize(center.Lat, grid.DegreesPerPixel())
+		values := []float64{RugosityFromWindow(window, dx, dy), TriFromWindow(window), 1 - math.Sqrt(nx*nx+ny*ny+nz*nz)/float64(vrmCount)}
+		for i := range opts.Bpi {
+			values = append(values, window[4]-sums[i]/float64(counts[i]))
+		}
+		return values
+	}
+
+	// a region across the antimeridian whose polar rows cover the globe, and one padded by its neighbourhoods
+	for _, bounds := range []Bounds{{West: 160, South: -90, East: -160, North: 90}, {West: -20, South: -30, East: 30, North: 30}} {
+		raster, err := RoughnessRaster(grid, bounds, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		region, err := grid.Region(bounds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j := range region.Height {
+			for i := range region.Width {
+				for band, value := range expected(grid.wrapX(region.X+i), region.Y+j) {
+					if actual := float64(raster.Bands[band][region.Index(i, j)]); math.Abs(actual-value) > 1e-3*max(1, math.Abs(value)) {
+						t.Errorf("%s at (%d,%d) of %+v: expected %g, got %g", raster.BandNames[band], i, j, bounds, value, actual)
+					}
+				}
+			}
+		}
+	}
+}
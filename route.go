@@ -0,0 +1,269 @@
+package gebco
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// CostFunc returns the cost of travelling a metre through a pixel of a grid relative to the shortest route, at
+// least one, or +Inf where the pixel must not be crossed.
+type CostFunc func(grid *Grid, x, y int) (float64, error)
+
+// CombineCosts returns a cost function multiplying the costs of several others.
+func CombineCosts(costs ...CostFunc) CostFunc {
+	return func(grid *Grid, x, y int) (float64, error) {
+		total := 1.0
+		for _, cost := range costs {
+			c, err := cost(grid, x, y)
+			if err != nil {
+				return 0, err
+			}
+			total *= c
+		}
+		return total, nil
+	}
+}
+
+// DepthCost returns a cost function that forbids land and charges penalty times as much for seafloor shallower
+// than shallowest or deeper than deepest metres, such as fishing grounds or abyssal trenches.
+func DepthCost(shallowest, deepest, penalty float64) CostFunc {
+	return func(grid *Grid, x, y int) (float64, error) {
+		sample, err := grid.Sample(x, y)
+		if err != nil {
+			return 0, err
+		}
+		if sample.IsLand() {
+			return math.Inf(1), nil
+		}
+		if depth := -float64(sample.SubIce); depth < shallowest || depth > deepest {
+			return penalty, nil
+		}
+		return 1, nil
+	}
+}
+
+// SlopeCost returns a cost function charging weight more per degree of seafloor slope, and forbidding slopes
+// steeper than maxSlope degrees unless it is zero.
+func SlopeCost(maxSlope, weight float64) CostFunc {
+	return func(grid *Grid, x, y int) (float64, error) {
+		terrain, err := grid.Terrain(x, y, GebcoDataSubIce)
+		if err != nil {
+			return 0, err
+		}
+		if maxSlope > 0 && terrain.Slope > maxSlope {
+			return math.Inf(1), nil
+		}
+		return 1 + weight*terrain.Slope, nil
+	}
+}
+
+// TidCost returns a cost function charging penalty times as much for pixels whose depth was never measured
+// directly, where the seafloor may hold surprises. Layers without type IDs are charged the penalty throughout.
+func TidCost(penalty float64) CostFunc {
+	return func(grid *Grid, x, y int) (float64, error) {
+		sample, err := grid.Sample(x, y)
+		if err != nil {
+			return 0, err
+		}
+		if sample.Tid.IsIndirect() || sample.Tid.IsUnknown() {
+			return penalty, nil
+		}
+		return 1, nil
+	}
+}
+
+// RouteOptions configures LeastCostRoute.
+type RouteOptions struct {
+	Cost     CostFunc // The cost of crossing each pixel, or nil for the shortest route.
+	Corridor float64  // The greatest distance in metres the route may stray from the great circle of each leg, or zero for no limit.
+	MaxNodes int      // The number of pixels a leg may explore before giving up, or zero for no limit.
+}
+
+// Route is a path across a grid through a series of waypoints.
+type Route struct {
+	Points []LatLng `json:"points"` // The waypoints and the centres of the pixels between them, with unwrapped longitudes.
+	Length float64  `json:"length"` // The length of the route in metres.
+	Cost   float64  `json:"cost"`   // The cost of the route, in metres weighted by the cost of the pixels crossed.
+}
+
+// Feature returns the route as a GeoJSON LineString feature, split at the antimeridian if it crosses it, with its
+// length and cost as properties.
+func (r Route) Feature() GeoJsonFeature {
+	return NewGeoJsonFeature(LineStringGeometry(r.Points), map[string]any{
+		"length": r.Length,
+		"cost":   r.Cost,
+	})
+}
+
+// Profile samples a grid at every point of the route, giving the depth profile along it.
+func (r Route) Profile(grid *Grid) ([]ProfileSample, error) {
+	samples := make([]ProfileSample, len(r.Points))
+	travelled := 0.0
+	for i, p := range r.Points {
+		if i > 0 {
+			travelled += Distance(r.Points[i-1], p)
+		}
+		sample, err := grid.SampleAt(p)
+		if err != nil {
+			return nil, err
+		}
+		samples[i] = ProfileSample{Distance: travelled, Position: LatLng{Lat: p.Lat, Lng: NormalizeLng(p.Lng)}, GebcoSample: sample}
+	}
+	return samples, nil
+}
+
+// LeastCostRoute finds the route through a series of waypoints that minimises the cost of the pixels it crosses,
+// searching each leg with A* over the eight neighbours of every pixel. A step between neighbouring pixel centres
+// costs its great-circle length times the mean cost of the two pixels, and the remaining great-circle distance
+// guides the search, so the route found is the cheapest while costs are at least one. Steps wrap across the
+// antimeridian. The pixels containing the waypoints may always be crossed, so that routes can start and end
+// on land. Consecutive waypoints may not be antipodal when a corridor is given, as no single great circle joins
+// them for it to follow.
+func LeastCostRoute(grid *Grid, waypoints []LatLng, opts RouteOptions) (*Route, error) {
+	if len(waypoints) < 2 {
+		return nil, fmt.Errorf("a route needs at least two waypoints, got %d", len(waypoints))
+	}
+	for i := 1; i < len(waypoints) && opts.Corridor > 0; i++ {
+		if antipodal(waypoints[i-1], waypoints[i]) {
+			return nil, fmt.Errorf("waypoints %v and %v are antipodal: add a waypoint between them to choose the great circle of the corridor", waypoints[i-1], waypoints[i])
+		}
+	}
+	route := &Route{Points: []LatLng{waypoints[0]}}
+	for i := 1; i < len(waypoints); i++ {
+		pixels, cost, err := searchLeg(grid, waypoints[i-1], waypoints[i], opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to route leg %d from %v to %v: %w", i, waypoints[i-1], waypoints[i], err)
+		}
+		route.Cost += cost
+
+		// the pixel centres between the waypoints, skipping those of the waypoint pixels themselves
+		leg := []LatLng{}
+		for j := 1; j < len(pixels)-1; j++ {
+			leg = append(leg, grid.PixelCenter(pixels[j]%grid.width, pixels[j]/grid.width))
+		}
+		leg = append(leg, waypoints[i])
+		for _, p := range leg {
+			previous := route.Points[len(route.Points)-1]
+			p.Lng += 360 * math.Round((previous.Lng-p.Lng)/360)
+			route.Length += Distance(previous, p)
+			route.Points = append(route.Points, p)
+		}
+	}
+	return route, nil
+}
+
+// routeNode is a pixel waiting in the A* open set, ordered by its estimated total cost.
+type routeNode struct {
+	pixel    int
+	estimate float64
+}
+
+type routeQueue []routeNode
+
+func (q routeQueue) Len() int           { return len(q) }
+func (q routeQueue) Less(i, j int) bool { return q[i].estimate < q[j].estimate }
+func (q routeQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *routeQueue) Push(x any)        { *q = append(*q, x.(routeNode)) }
+func (q *routeQueue) Pop() any {
+	old := *q
+	node := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return node
+}
+
+// searchLeg finds the cheapest path of pixels from the pixel containing start to the pixel containing end,
+// returning the pixel indices of the path including both ends and its cost.
+func searchLeg(grid *Grid, start, end LatLng, opts RouteOptions) ([]int, float64, error) {
+	index := func(x, y int) int {
+		return y*grid.width + x
+	}
+	startX, startY := grid.Pixel(start)
+	endX, endY := grid.Pixel(end)
+	startPixel, endPixel := index(startX, startY), index(endX, endY)
+	goal := grid.PixelCenter(endX, endY)
+
+	costs := map[int]float64{}
+	pixelCost := func(pixel int) (float64, error) {
+		if cost, ok := costs[pixel]; ok {
+			return cost, nil
+		}
+		cost := 1.0
+		x, y := pixel%grid.width, pixel/grid.width
+		if opts.Corridor > 0 && DistanceToArc(grid.PixelCenter(x, y), start, end) > opts.Corridor {
+			cost = math.Inf(1)
+		} else if opts.Cost != nil {
+			var err error
+			if cost, err = opts.Cost(grid, x, y); err != nil {
+				return 0, err
+			}
+		}
+		if pixel == startPixel || pixel == endPixel {
+			cost = min(cost, 1)
+		}
+		costs[pixel] = cost
+		return cost, nil
+	}
+
+	travelled := map[int]float64{startPixel: 0}
+	from := map[int]int{}
+	closed := map[int]bool{}
+	queue := &routeQueue{{pixel: startPixel, estimate: Distance(grid.PixelCenter(startX, startY), goal)}}
+	for queue.Len() > 0 {
+		node := heap.Pop(queue).(routeNode)
+		if closed[node.pixel] {
+			continue
+		}
+		if node.pixel == endPixel {
+			path := []int{endPixel}
+			for pixel := endPixel; pixel != startPixel; {
+				pixel = from[pixel]
+				path = append(path, pixel)
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path, travelled[endPixel], nil
+		}
+		closed[node.pixel] = true
+		if opts.MaxNodes > 0 && len(closed) > opts.MaxNodes {
+			return nil, 0, fmt.Errorf("gave up after exploring %d pixels", opts.MaxNodes)
+		}
+
+		x, y := node.pixel%grid.width, node.pixel/grid.width
+		here := grid.PixelCenter(x, y)
+		hereCost, err := pixelCost(node.pixel)
+		if err != nil {
+			return nil, 0, err
+		}
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				ny := y + dy
+				if (dx == 0 && dy == 0) || ny < 0 || ny >= grid.height {
+					continue
+				}
+				nx := grid.wrapX(x + dx)
+				neighbour := index(nx, ny)
+				if closed[neighbour] {
+					continue
+				}
+				neighbourCost, err := pixelCost(neighbour)
+				if err != nil {
+					return nil, 0, err
+				}
+				if math.IsInf(neighbourCost, 1) {
+					continue
+				}
+				there := grid.PixelCenter(nx, ny)
+				cost := travelled[node.pixel] + Distance(here, there)*(hereCost+neighbourCost)/2
+				if previous, ok := travelled[neighbour]; ok && previous <= cost {
+					continue
+				}
+				travelled[neighbour] = cost
+				from[neighbour] = node.pixel
+				heap.Push(queue, routeNode{pixel: neighbour, estimate: cost + Distance(there, goal)})
+			}
+		}
+	}
+	return nil, 0, fmt.Errorf("no route avoids the forbidden pixels")
+}
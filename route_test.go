@@ -0,0 +1,84 @@
+package gebco
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistanceToArc(t *testing.T) {
+	a, b := LatLng{Lat: 0, Lng: 0}, LatLng{Lat: 0, Lng: 10}
+	degree := Distance(LatLng{}, LatLng{Lat: 1})
+	cases := []struct {
+		name     string
+		p        LatLng
+		expected float64
+	}{
+		{"on the arc", LatLng{Lat: 0, Lng: 5}, 0},
+		{"beside the arc", LatLng{Lat: 1, Lng: 5}, degree},
+		{"before the start", LatLng{Lat: 0, Lng: -2}, 2 * degree},
+		{"past the end", LatLng{Lat: 0, Lng: 13}, 3 * degree},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if actual := DistanceToArc(c.p, a, b); math.Abs(actual-c.expected) > 1 {
+				t.Errorf("expected %f, got %f", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestLeastCostRoute(t *testing.T) {
+	// 5 degree pixels of deep sea, with a land barrier along the equator from 30W to 30E and a shallow bank
+	// north of it
+	dataset := writeTestDataset(t, 72, 8, func(x, y int) GebcoSample {
+		switch {
+		case x >= 30 && x < 42 && (y == 17 || y == 18):
+			return GebcoSample{Ice: 50, SubIce: 50, Tid: GebcoTypeLand}
+		case x >= 34 && x < 38 && (y == 15 || y == 16):
+			return GebcoSample{Ice: -50, SubIce: -50, Tid: GebcoTypeMultiBeam}
+		}
+		return GebcoSample{Ice: -3000, SubIce: -3000, Tid: GebcoTypeMultiBeam}
+	})
+	grid := dataset.Grid()
+	start, end := LatLng{Lat: -22, Lng: 1}, LatLng{Lat: 22, Lng: 1}
+
+	direct, err := LeastCostRoute(grid, []LatLng{{Lat: 2.5, Lng: 167.5}, {Lat: 2.5, Lng: -167.5}}, RouteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := Distance(LatLng{Lat: 2.5, Lng: 167.5}, LatLng{Lat: 2.5, Lng: 192.5}); math.Abs(direct.Length-expected) > 0.001*expected {
+		t.Errorf("expected a direct route of %f across the antimeridian, got %f", expected, direct.Length)
+	}
+	if last := direct.Points[len(direct.Points)-1]; last.Lng != 192.5 {
+		t.Errorf("expected the route to end at unwrapped longitude 192.5, got %v", last)
+	}
+
+	cost := DepthCost(200, 8000, 10)
+	route, err := LeastCostRoute(grid, []LatLng{start, end}, RouteOptions{Cost: cost})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if route.Length < Distance(start, end)*1.2 {
+		t.Errorf("expected the route to detour around the barrier, got length %f", route.Length)
+	}
+	profile, err := route.Profile(grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sample := range profile {
+		if sample.IsLand() || sample.Ice > -200 {
+			t.Errorf("expected the route to avoid land and the shallow bank, got %+v", sample)
+		}
+	}
+	if profile[len(profile)-1].Distance != route.Length {
+		t.Errorf("expected the profile to end at the route length %f, got %f", route.Length, profile[len(profile)-1].Distance)
+	}
+
+	if _, err := LeastCostRoute(grid, []LatLng{start, end}, RouteOptions{Cost: cost, Corridor: 1000e3}); err == nil {
+		t.Errorf("expected no route around the barrier within a narrow corridor")
+	}
+	antipodes := []LatLng{{Lat: 10, Lng: 20}, {Lat: -10, Lng: -160}}
+	if _, err := LeastCostRoute(grid, antipodes, RouteOptions{Corridor: 1000e3}); err == nil {
+		t.Errorf("expected an error for a corridor between antipodal waypoints")
+	}
+}
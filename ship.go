@@ -0,0 +1,139 @@
+package gebco
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// DraughtCost returns a cost function forbidding pixels whose ice surface lies less than depth metres below sea
+// level.
+func DraughtCost(depth float64) CostFunc {
+	return func(grid *Grid, x, y int) (float64, error) {
+		sample, err := grid.Sample(x, y)
+		if err != nil {
+			return 0, err
+		}
+		if float64(sample.Ice) > -depth {
+			return math.Inf(1), nil
+		}
+		return 1, nil
+	}
+}
+
+// NoGoCost returns a cost function forbidding pixels whose centres lie within any of the rings, each repeating
+// its first position last.
+func NoGoCost(rings [][]LatLng) CostFunc {
+	return func(grid *Grid, x, y int) (float64, error) {
+		center := grid.PixelCenter(x, y)
+		for _, ring := range rings {
+			if ringContains(ring, center) {
+				return math.Inf(1), nil
+			}
+		}
+		return 1, nil
+	}
+}
+
+// corridorCost returns a cost function forbidding pixels whose centres lie outside the given pixels of another,
+// usually coarser, grid.
+func corridorCost(coarse *Grid, pixels map[int]bool) CostFunc {
+	return func(grid *Grid, x, y int) (float64, error) {
+		cx, cy := coarse.Pixel(grid.PixelCenter(x, y))
+		if !pixels[cy*coarse.width+cx] {
+			return math.Inf(1), nil
+		}
+		return 1, nil
+	}
+}
+
+// ShipRouteOptions configures RouteForDraught.
+type ShipRouteOptions struct {
+	Draught   float64    // The draught of the vessel in metres.
+	Clearance float64    // The under-keel clearance in metres to keep beneath the vessel.
+	NoGo      [][]LatLng // Rings around areas the route must avoid, each repeating its first position last.
+	MaxNodes  int        // The number of pixels each leg may explore at each resolution before giving up, or zero for no limit.
+}
+
+// ShipRoute is a route for a vessel with its shoalest point.
+type ShipRoute struct {
+	Route
+	Shoalest ProfileSample `json:"shoalest"` // The shallowest point of the route at full resolution.
+}
+
+// Feature returns the route as a GeoJSON LineString feature, split at the antimeridian if it crosses it, with its
+// length and the depth and position of its shoalest point as properties.
+func (r ShipRoute) Feature() GeoJsonFeature {
+	return NewGeoJsonFeature(LineStringGeometry(r.Points), map[string]any{
+		"length":        r.Length,
+		"shoalestDepth": -float64(r.Shoalest.Ice),
+		"shoalestLat":   r.Shoalest.Position.Lat,
+		"shoalestLng":   r.Shoalest.Position.Lng,
+	})
+}
+
+// RouteForDraught finds the shortest route through a series of waypoints that keeps the ice surface at least the
+// draught plus the clearance below the vessel and stays out of the no-go areas, treating every other pixel as an
+// obstacle. The search runs coarse to fine over the dataset's shoal overviews, whose pixels hold the shoalest
+// depth they cover so a route found on them is safe at full resolution, and then the full resolution grid, each
+// level searching only the pixels around the route of the level before. Where a coarse level finds no route,
+// because a channel is narrower than its pixels, the next level searches in full; where a coarse level finds a
+// longer way round instead, the route keeps to it. The waypoints themselves may lie in shallow water, such as in a
+// harbour.
+func RouteForDraught(dataset *Dataset, waypoints []LatLng, opts ShipRouteOptions) (*ShipRoute, error) {
+	if opts.Draught <= 0 || opts.Clearance < 0 {
+		return nil, fmt.Errorf("invalid draught %g and clearance %g", opts.Draught, opts.Clearance)
+	}
+	cost := DraughtCost(opts.Draught + opts.Clearance)
+	if len(opts.NoGo) > 0 {
+		cost = CombineCosts(cost, NoGoCost(opts.NoGo))
+	}
+
+	levels := slices.Clone(dataset.ShoalGrids())
+	slices.Reverse(levels)
+	levels = append(levels, dataset.Grid())
+
+	var route *Route
+	var previous *Grid
+	var corridor map[int]bool
+	for _, grid := range levels {
+		var err error
+		if corridor != nil {
+			route, err = LeastCostRoute(grid, waypoints, RouteOptions{Cost: CombineCosts(corridorCost(previous, corridor), cost), MaxNodes: opts.MaxNodes})
+		}
+		if corridor == nil || err != nil {
+			// the corridor can pinch off where a waypoint's coarse pixel was crossed regardless of depth
+			route, err = LeastCostRoute(grid, waypoints, RouteOptions{Cost: cost, MaxNodes: opts.MaxNodes})
+		}
+		if err != nil {
+			if grid == dataset.Grid() {
+				return nil, err
+			}
+			corridor = nil
+			continue
+		}
+
+		// the next level searches the pixels of this route and their neighbours
+		previous, corridor = grid, map[int]bool{}
+		for _, p := range route.Points {
+			x, y := grid.Pixel(p)
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					corridor[grid.clampY(y+dy)*grid.width+grid.wrapX(x+dx)] = true
+				}
+			}
+		}
+	}
+
+	profile, err := route.Profile(dataset.Grid())
+	if err != nil {
+		return nil, err
+	}
+	shipRoute := &ShipRoute{Route: *route, Shoalest: profile[0]}
+	for _, sample := range profile {
+		if sample.Ice > shipRoute.Shoalest.Ice {
+			shipRoute.Shoalest = sample
+		}
+	}
+	return shipRoute, nil
+}
@@ -0,0 +1,106 @@
+package gebco
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/gracefulearth/gopixi"
+)
+
+// shoalTestLayers makes a shoal overview layer of each of the given sizes for writeTestDataset, holding the highest
+// ice and sub-ice elevations of the pixels of a grid of the given width it covers.
+func shoalTestLayers(width int, shoalSizes []int, value func(x, y int) GebcoSample) []testLayer {
+	layers := []testLayer{}
+	for _, size := range shoalSizes {
+		factor := width / size
+		layers = append(layers, testLayer{
+			layer: gopixi.NewLayer(fmt.Sprintf("%s_%d", ShoalLayerPrefix, size),
+				gopixi.DimensionSet{
+					{Name: "lng", TileSize: size / 2, Size: size},
+					{Name: "lat", TileSize: size / 2, Size: size / 2}},
+				gopixi.ChannelSet{
+					{Name: "ice", Type: gopixi.ChannelInt16},
+					{Name: "sub-ice", Type: gopixi.ChannelInt16}},
+			),
+			sample: func(coord gopixi.SampleCoordinate) gopixi.Sample {
+				ice, subIce := int16(math.MinInt16), int16(math.MinInt16)
+				for y := coord[1] * factor; y < (coord[1]+1)*factor; y++ {
+					for x := coord[0] * factor; x < (coord[0]+1)*factor; x++ {
+						sample := value(x, y)
+						ice, subIce = max(ice, sample.Ice), max(subIce, sample.SubIce)
+					}
+				}
+				return gopixi.Sample{ice, subIce}
+			},
+		})
+	}
+	return layers
+}
+
+func TestRouteForDraught(t *testing.T) {
+	// 5 degree pixels of 100 m deep water, split along the antimeridian by a 5 m shoal and along the prime meridian
+	// by one that is open only where passage is, with a 20 m deep harbour at the start
+	shoal := func(passage func(y int) bool) func(x, y int) GebcoSample {
+		return func(x, y int) GebcoSample {
+			switch {
+			case x == 10 && y == 20:
+				return GebcoSample{Ice: -20, SubIce: -20, Tid: GebcoTypeMultiBeam}
+			case x == 0 || x == 36 && !passage(y):
+				return GebcoSample{Ice: -5, SubIce: -5, Tid: GebcoTypeMultiBeam}
+			}
+			return GebcoSample{Ice: -100, SubIce: -100, Tid: GebcoTypeMultiBeam}
+		}
+	}
+	start, end := LatLng{Lat: -12.5, Lng: -127.5}, LatLng{Lat: 2.5, Lng: 27.5}
+	crosses := func(route *ShipRoute, lat float64) bool {
+		for _, p := range route.Points {
+			if p.Lat == lat && p.Lng == 2.5 {
+				return true
+			}
+		}
+		return false
+	}
+
+	// a one pixel channel is closed in the shoal overviews, so the route is found at full resolution
+	channelDepths := shoal(func(y int) bool { return y == 17 })
+	channel := writeTestDataset(t, 72, 12, channelDepths, shoalTestLayers(72, []int{36, 18}, channelDepths)...)
+	if len(channel.ShoalGrids()) != 2 || len(channel.Grids()) != 1 {
+		t.Fatalf("expected 2 shoal grids kept apart from 1 grid, got %d and %d", len(channel.ShoalGrids()), len(channel.Grids()))
+	}
+	route, err := RouteForDraught(channel, []LatLng{start, end}, ShipRouteOptions{Draught: 10, Clearance: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !crosses(route, 2.5) {
+		t.Errorf("expected the route through the channel, got %v", route.Points)
+	}
+	if route.Shoalest.Ice != -20 || route.Shoalest.Position != start {
+		t.Errorf("expected the harbour to be the shoalest point, got %+v", route.Shoalest)
+	}
+
+	// a no-go area over the channel leaves no route
+	noGo := [][]LatLng{{{Lat: -5, Lng: -5}, {Lat: -5, Lng: 10}, {Lat: 10, Lng: 10}, {Lat: 10, Lng: -5}, {Lat: -5, Lng: -5}}}
+	if _, err := RouteForDraught(channel, []LatLng{start, end}, ShipRouteOptions{Draught: 10, Clearance: 2, NoGo: noGo}); err == nil {
+		t.Errorf("expected no route around the no-go area")
+	}
+
+	// a wide passage stays open in the shoal overviews, which lead the route through it
+	passageDepths := shoal(func(y int) bool { return y >= 12 && y < 24 })
+	passage := writeTestDataset(t, 72, 12, passageDepths, shoalTestLayers(72, []int{36, 18}, passageDepths)...)
+	route, err = RouteForDraught(passage, []LatLng{start, end}, ShipRouteOptions{Draught: 10, Clearance: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	crossed := false
+	for y := 12; y < 24; y++ {
+		crossed = crossed || crosses(route, 90-(float64(y)+0.5)*5)
+	}
+	if !crossed || route.Points[0] != start || route.Points[len(route.Points)-1] != end {
+		t.Errorf("expected the route from %v to %v through the passage, got %v", start, end, route.Points)
+	}
+
+	if _, err := RouteForDraught(passage, []LatLng{start, end}, ShipRouteOptions{Draught: 150}); err == nil {
+		t.Errorf("expected no route for a draught deeper than the sea")
+	}
+}
@@ -0,0 +1,74 @@
+package gebco
+
+import (
+	"math"
+)
+
+// Terrain holds the first and second order surface derivatives at a pixel, computed from its 3x3 neighbourhood
+// following Zevenbergen and Thorne (1987).
+type Terrain struct {
+	Slope            float64 `json:"slope"`            // Steepest gradient in degrees from horizontal.
+	Aspect           float64 `json:"aspect"`           // Downslope direction in degrees clockwise from north, -1 where flat.
+	ProfileCurvature float64 `json:"profileCurvature"` // Curvature in the direction of steepest slope, in 1/m.
+	PlanCurvature    float64 `json:"planCurvature"`    // Curvature across the direction of steepest slope, in 1/m.
+}
+
+// TerrainFromWindow computes terrain derivatives from a 3x3 window of elevations in row-major order starting at
+// the north-west neighbour, with dx and dy the east-west and north-south pixel spacing in metres.
+func TerrainFromWindow(window [9]float64, dx, dy float64) Terrain {
+	z1, z2, z3 := window[0], window[1], window[2]
+	z4, z5, z6 := window[3], window[4], window[5]
+	z7, z8, z9 := window[6], window[7], window[8]
+
+	d := ((z4+z6)/2 - z5) / (dx * dx)
+	e := ((z2+z8)/2 - z5) / (dy * dy)
+	f := (-z1 + z3 + z7 - z9) / (4 * dx * dy)
+	g := (z6 - z4) / (2 * dx) // eastward gradient
+	h := (z2 - z8) / (2 * dy) // northward gradient
+
+	terrain := Terrain{Aspect: -1}
+	gradient := g*g + h*h
+	terrain.Slope = degrees(math.Atan(math.Sqrt(gradient)))
+	if gradient > 0 {
+		terrain.Aspect = math.Mod(degrees(math.Atan2(-g, -h))+360, 360)
+		terrain.ProfileCurvature = -2 * (d*g*g + e*h*h + f*g*h) / gradient
+		terrain.PlanCurvature = 2 * (d*h*h + e*g*g - f*g*h) / gradient
+	}
+	return terrain
+}
+
+// Window returns the 3x3 neighbourhood of elevations around a pixel for the given surface, in the order expected
+// by TerrainFromWindow. Neighbours wrap around the antimeridian and continue over the poles onto the opposite
+// meridian, so windows never have a seam.
+func (g *Grid) Window(x, y int, surface GebcoDataType) ([9]float64, error) {
+	var window [9]float64
+	buf := g.newSampleBuffer()
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			nx, ny := g.acrossPole(x+dx, y+dy)
+			sample, err := g.sampleInto(nx, ny, buf)
+			if err != nil {
+				return window, err
+			}
+			window[(dy+1)*3+dx+1] = sample.Value(surface)
+		}
+	}
+	return window, nil
+}
+
+// Terrain computes slope, aspect and curvature at a pixel of the given surface, using the metric pixel spacing
+// at the latitude of the pixel.
+func (g *Grid) Terrain(x, y int, surface GebcoDataType) (Terrain, error) {
+	window, err := g.Window(x, y, surface)
+	if err != nil {
+		return Terrain{}, err
+	}
+	dx, dy := CellSize(g.PixelCenter(x, y).Lat, g.DegreesPerPixel())
+	return TerrainFromWindow(window, dx, dy), nil
+}
+
+// TerrainAt computes slope, aspect and curvature at the pixel containing a position.
+func (g *Grid) TerrainAt(p LatLng, surface GebcoDataType) (Terrain, error) {
+	x, y := g.Pixel(p)
+	return g.Terrain(x, y, surface)
+}
@@ -0,0 +1,56 @@
+package gebco
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTerrainFromWindow(t *testing.T) {
+	cases := []struct {
+		name           string
+		window         [9]float64
+		expectedSlope  float64
+		expectedAspect float64
+	}{
+		{"flat", [9]float64{5, 5, 5, 5, 5, 5, 5, 5, 5}, 0, -1},
+		{"rising to the east", [9]float64{0, 1, 2, 0, 1, 2, 0, 1, 2}, 45, 270},
+		{"rising to the north", [9]float64{2, 2, 2, 1, 1, 1, 0, 0, 0}, 45, 180},
+		{"rising to the south west", [9]float64{0, 0, 0, 1, 0, 0, 2, 1, 0}, math.Atan(math.Sqrt2/2) * 180 / math.Pi, 45},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			terrain := TerrainFromWindow(c.window, 1, 1)
+			if math.Abs(terrain.Slope-c.expectedSlope) > 1e-9 {
+				t.Errorf("expected slope %f, got %f", c.expectedSlope, terrain.Slope)
+			}
+			if math.Abs(terrain.Aspect-c.expectedAspect) > 1e-9 {
+				t.Errorf("expected aspect %f, got %f", c.expectedAspect, terrain.Aspect)
+			}
+		})
+	}
+}
+
+func TestGridTerrainWraps(t *testing.T) {
+	// elevation rises steadily to the east, except for the jump back down at the antimeridian
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		return GebcoSample{Ice: int16(100 * x), SubIce: int16(100 * x)}
+	})
+	grid := dataset.Grid()
+
+	inner, err := grid.Terrain(10, 9, GebcoDataIce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inner.Aspect != 270 {
+		t.Errorf("expected west facing slope, got aspect %f", inner.Aspect)
+	}
+
+	// at the western edge the neighbour across the antimeridian is the highest pixel, so the slope faces east
+	edge, err := grid.Terrain(0, 9, GebcoDataIce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edge.Aspect != 90 {
+		t.Errorf("expected east facing slope at antimeridian, got aspect %f", edge.Aspect)
+	}
+}
@@ -0,0 +1,95 @@
+package gebco
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+	"sync"
+
+	"github.com/gracefulearth/go-colorext"
+	"github.com/gracefulearth/gopixi"
+)
+
+const (
+	tifSourceTileSize = GtiffTileSize / 40 // The size of the Pixi tiles a GEBCO tiff source is read in.
+	tifSourceMaxTiles = 256                // The number of encoded tiles a GEBCO tiff source keeps in memory, most recently used.
+)
+
+// tifTileSource encodes Pixi tiles from a folder of GEBCO tiff files of one release, so that the release can be
+// read as a Grid without first building a Pixi file. The tiff files of one GEBCO tile are held in memory at a time.
+type tifTileSource struct {
+	folder string
+	files  []GebcoTifLayer
+	header gopixi.Header
+	layer  gopixi.Layer
+
+	lock             sync.Mutex
+	loaded           int // the index of the GEBCO tile whose images are loaded, -1 when none is
+	ice, subIce, tid image.Image
+}
+
+// OpenGeoTiffGrid opens the GEBCO tiff files of a release in a folder as a full resolution grid, keeping the most
+// recently read tiles in memory. Reading loads and decodes a whole GEBCO tile (several gigabytes) whenever it moves
+// to a tile of another one that is not cached, so regions should be read tile by tile grouped by GEBCO tile, as
+// DiffReleases does.
+func OpenGeoTiffGrid(folder string, year int) (*Grid, error) {
+	files := GebcoLayeredTiles(year)
+	if missing := CheckDirectoryComplete(folder, files); len(missing) > 0 {
+		return nil, fmt.Errorf("missing %d GEBCO %d files in %s: %s", len(missing), year, folder, strings.Join(missing, ", "))
+	}
+	source := &tifTileSource{
+		folder: folder,
+		files:  files,
+		header: gopixi.NewHeader(binary.NativeEndian, gopixi.OffsetSize8),
+		layer: gopixi.NewLayer(fmt.Sprintf("gebco_tif_%d", year),
+			gopixi.DimensionSet{
+				{Name: "lng", TileSize: tifSourceTileSize, Size: TotalWidth},
+				{Name: "lat", TileSize: tifSourceTileSize, Size: TotalHeight}},
+			gopixi.ChannelSet{
+				{Name: "ice", Type: gopixi.ChannelInt16},
+				{Name: "sub-ice", Type: gopixi.ChannelInt16},
+				{Name: "tid", Type: gopixi.ChannelUint8}},
+		),
+		loaded: -1,
+	}
+	return NewGrid(newLruTileSource(source.header, source.layer, source.readTile, tifSourceMaxTiles))
+}
+
+// readTile encodes a Pixi tile from the tiff files of its GEBCO tile, loading them if another tile is loaded.
+func (s *tifTileSource) readTile(tile int) ([]byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	tilesAcross := s.layer.Dimensions[0].Tiles()
+	xStart, yStart := (tile%tilesAcross)*tifSourceTileSize, (tile/tilesAcross)*tifSourceTileSize
+	gebcoTile := xStart/GtiffTileSize + (yStart/GtiffTileSize)*TilesX
+	if gebcoTile != s.loaded {
+		var err error
+		s.ice, s.subIce, s.tid, err = s.files[gebcoTile].Load(s.folder)
+		if err != nil {
+			s.loaded = -1
+			return nil, fmt.Errorf("failed to load GEBCO tile layer: %w", err)
+		}
+		s.loaded = gebcoTile
+	}
+
+	data := make([]byte, s.layer.DiskTileSize(tile))
+	offset := 0
+	xInGebco, yInGebco := xStart%GtiffTileSize, yStart%GtiffTileSize
+	for y := yInGebco; y < yInGebco+tifSourceTileSize; y++ {
+		for x := xInGebco; x < xInGebco+tifSourceTileSize; x++ {
+			values := []any{
+				s.ice.At(x, y).(colorext.GrayS16).Y,
+				s.subIce.At(x, y).(colorext.GrayS16).Y,
+				s.tid.At(x, y).(color.Gray).Y,
+			}
+			for i, channel := range s.layer.Channels {
+				channel.PutValue(values[i], s.header.ByteOrder, data[offset:])
+				offset += channel.Size()
+			}
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,135 @@
+package gebco
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"sync"
+
+	"github.com/gracefulearth/gopixi"
+)
+
+type cachedTile struct {
+	tile    int
+	ready   chan struct{} // closed once data or err is set
+	data    []byte
+	err     error
+	element *list.Element
+}
+
+// lruTileCache implements gopixi.TileAccessLayer, keeping the most recently used decoded tiles of a layer in
+// memory. Concurrent requests for the same missing tile wait for a single read instead of each decoding it, and
+// requests for tiles already cached never wait on reads of other tiles.
+type lruTileCache struct {
+	header gopixi.Header
+	layer  gopixi.Layer
+	read   func(tile int) ([]byte, error) // reads a decoded tile that is not cached
+
+	cacheLock sync.Mutex
+	tiles     map[int]*cachedTile
+	recent    *list.List // most recently used tiles at the front
+	maxTiles  int
+}
+
+var _ gopixi.TileAccessLayer = (*lruTileCache)(nil)
+
+// newLruTileCache caches the tiles of a layer read from a Pixi file. Only the seek and read of the compressed bytes
+// of a tile hold the backing stream, so that tiles are decompressed in parallel.
+func newLruTileCache(backing io.ReadSeeker, header gopixi.Header, layer gopixi.Layer, maxTiles int) *lruTileCache {
+	readLock := sync.Mutex{} // serializes seeks and reads on backing
+	return newLruTileSource(header, layer, func(tile int) ([]byte, error) {
+		if tile < 0 || tile >= len(layer.TileBytes) || layer.TileBytes[tile] == 0 {
+			return nil, gopixi.ErrTileNotFound{TileIndex: tile}
+		}
+		offset := layer.TileOffsets[tile]
+		raw := make([]byte, layer.TileBytes[tile]+4) // the compressed tile followed by its checksum
+		readLock.Lock()
+		_, err := backing.Seek(offset, io.SeekStart)
+		if err == nil {
+			_, err = io.ReadFull(backing, raw)
+		}
+		readLock.Unlock()
+		if err != nil {
+			return nil, err
+		}
+
+		data := make([]byte, layer.DiskTileSize(tile))
+		if err := layer.ReadTile(rawTile{Reader: bytes.NewReader(raw), offset: offset}, header, tile, data); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}, maxTiles)
+}
+
+// rawTile reads the bytes of a tile read from a Pixi file as if from the file, for gopixi.Layer.ReadTile to seek to
+// the offset of the tile in it.
+type rawTile struct {
+	*bytes.Reader
+	offset int64 // the offset in the file of the first byte
+}
+
+func (r rawTile) Seek(offset int64, whence int) (int64, error) {
+	if whence == io.SeekStart {
+		offset -= r.offset
+	}
+	position, err := r.Reader.Seek(offset, whence)
+	return position + r.offset, err
+}
+
+// newLruTileSource caches the tiles of a layer produced by a read function, which may be called concurrently for
+// different tiles.
+func newLruTileSource(header gopixi.Header, layer gopixi.Layer, read func(tile int) ([]byte, error), maxTiles int) *lruTileCache {
+	return &lruTileCache{
+		header:   header,
+		layer:    layer,
+		read:     read,
+		tiles:    make(map[int]*cachedTile),
+		recent:   list.New(),
+		maxTiles: max(1, maxTiles),
+	}
+}
+
+func (c *lruTileCache) Layer() gopixi.Layer {
+	return c.layer
+}
+
+func (c *lruTileCache) Header() gopixi.Header {
+	return c.header
+}
+
+func (c *lruTileCache) Tile(tile int) ([]byte, error) {
+	c.cacheLock.Lock()
+	entry, found := c.tiles[tile]
+	if found {
+		c.recent.MoveToFront(entry.element)
+		c.cacheLock.Unlock()
+		<-entry.ready
+		return entry.data, entry.err
+	}
+
+	entry = &cachedTile{tile: tile, ready: make(chan struct{})}
+	entry.element = c.recent.PushFront(entry)
+	c.tiles[tile] = entry
+	for c.recent.Len() > c.maxTiles {
+		oldest := c.recent.Back()
+		c.recent.Remove(oldest)
+		delete(c.tiles, oldest.Value.(*cachedTile).tile)
+	}
+	c.cacheLock.Unlock()
+
+	data, err := c.read(tile)
+	entry.data, entry.err = data, err
+	close(entry.ready)
+
+	if err != nil {
+		// forget failed reads so that a later request can retry
+		c.cacheLock.Lock()
+		if c.tiles[tile] == entry {
+			c.recent.Remove(entry.element)
+			delete(c.tiles, tile)
+		}
+		c.cacheLock.Unlock()
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,29 @@
+package gebco
+
+import (
+	"testing"
+
+	"github.com/gracefulearth/gopixi"
+)
+
+func TestLruTileSource(t *testing.T) {
+	reads := map[int]int{}
+	cache := newLruTileSource(gopixi.Header{}, gopixi.Layer{}, func(tile int) ([]byte, error) {
+		reads[tile]++
+		return []byte{byte(tile)}, nil
+	}, 2)
+
+	// reading tile 0 again keeps it cached when tile 2 evicts the least recently used tile 1
+	for _, tile := range []int{0, 1, 0, 2, 0, 1} {
+		data, err := cache.Tile(tile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data[0] != byte(tile) {
+			t.Errorf("expected the data of tile %d, got %v", tile, data)
+		}
+	}
+	if reads[0] != 1 || reads[1] != 2 || reads[2] != 1 {
+		t.Errorf("expected tile 1 read again after its eviction, got reads %v", reads)
+	}
+}
@@ -0,0 +1,200 @@
+package gebco
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+const (
+	Gravity float64 = 9.80665 // Standard gravity in metres per second squared.
+)
+
+// LongWaveSpeed returns the speed in metres per second of a shallow water (long) wave, such as a tsunami, over
+// water of the given depth in metres.
+func LongWaveSpeed(depth float64) float64 {
+	return math.Sqrt(Gravity * depth)
+}
+
+// TravelTimeOptions configures TsunamiTravelTimes.
+type TravelTimeOptions struct {
+	Region   Bounds   // The region to solve over, expanded to whole pixels of the grid.
+	Sources  []LatLng // The positions the wave starts from at time zero.
+	MinDepth float64  // The depth in metres that shallower water is treated as, keeping waves moving up to the coast.
+}
+
+// Isochrone is a line joining the positions a wave reaches at the same time. Later times lie on its left when
+// viewed on a north-up map.
+type Isochrone struct {
+	Hours  float64  `json:"hours"`
+	Closed bool     `json:"closed"` // Whether the line is a ring, in which case its last point repeats its first.
+	Points []LatLng `json:"points"` // Longitudes are unwrapped so consecutive points never differ by more than 180 degrees.
+}
+
+// Feature returns the isochrone as a GeoJSON LineString feature, split at the antimeridian if it crosses it, with
+// its travel time as a property.
+func (c Isochrone) Feature() GeoJsonFeature {
+	return NewGeoJsonFeature(LineStringGeometry(c.Points), map[string]any{
+		"hours":  c.Hours,
+		"closed": c.Closed,
+	})
+}
+
+// TravelTimes holds the times a wave takes to reach every pixel of a region.
+type TravelTimes struct {
+	Raster *Raster // A "travel-time" band in hours, NaN on land and in water the wave cannot reach.
+	region GridRegion
+}
+
+// Isochrones traces the lines the wave reaches at each of the given times in hours with marching squares. Lines
+// end at the coast and the edge of the region.
+func (t *TravelTimes) Isochrones(hours []float64) []Isochrone {
+	// unreached pixels arrive last, so that lines run along the coast rather than around it
+	values := make([]float32, len(t.Raster.Bands[0]))
+	for i, value := range t.Raster.Bands[0] {
+		if math.IsNaN(float64(value)) {
+			value = float32(math.Inf(1))
+		}
+		values[i] = value
+	}
+	isochrones := []Isochrone{}
+	for _, level := range hours {
+		for _, contour := range contourLevel(t.region, values, level) {
+			isochrones = append(isochrones, Isochrone{Hours: level, Closed: contour.Closed, Points: contour.Points})
+		}
+	}
+	return isochrones
+}
+
+// TsunamiTravelTimes estimates the time a tsunami takes to travel from its sources to every pixel of water in a
+// region of a grid, moving at the long wave speed of the depth below the ice surface. The eikonal equation is
+// solved by fast marching: pixels are fixed in order of arrival, each from the upwind neighbours already fixed
+// along and across its row, with their distances on the sphere so that waves keep their shape towards the poles.
+// Land blocks the wave, and a region spanning the globe wraps across the antimeridian. Sources may lie on the
+// coast, in which case the wave starts into the water around them.
+func TsunamiTravelTimes(grid *Grid, opts TravelTimeOptions) (*TravelTimes, error) {
+	if len(opts.Sources) == 0 {
+		return nil, fmt.Errorf("no tsunami sources")
+	}
+	if opts.MinDepth <= 0 {
+		return nil, fmt.Errorf("invalid minimum depth %g", opts.MinDepth)
+	}
+	region, err := grid.Region(opts.Region)
+	if err != nil {
+		return nil, err
+	}
+	width, height := region.Width, region.Height
+	samples, err := region.ReadSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	// the time in seconds to cross a metre of each pixel, or +Inf on land
+	slowness := make([]float64, len(samples))
+	for i, sample := range samples {
+		if sample.Ice >= 0 {
+			slowness[i] = math.Inf(1)
+		} else {
+			slowness[i] = 1 / LongWaveSpeed(max(opts.MinDepth, -float64(sample.Ice)))
+		}
+	}
+	rowDx := make([]float64, height)
+	step := grid.DegreesPerPixel()
+	_, dy := CellSize(0, step)
+	for j := range height {
+		rowDx[j], _ = CellSize(region.Position(0.5, float64(j)+0.5).Lat, step)
+	}
+
+	seconds := make([]float64, len(samples))
+	for i := range seconds {
+		seconds[i] = math.Inf(1)
+	}
+	fixed := make([]bool, len(samples))
+	queue := &routeQueue{}
+	for _, source := range opts.Sources {
+		x, y := grid.Pixel(source)
+		i, j := (x-region.X+grid.width)%grid.width, y-region.Y
+		if i >= width || j < 0 || j >= height {
+			return nil, fmt.Errorf("source %v lies outside the region", source)
+		}
+		seconds[region.Index(i, j)] = 0
+		heap.Push(queue, routeNode{pixel: region.Index(i, j), estimate: 0})
+	}
+
+	// across returns the index of the pixel di columns along a row, wrapping across the antimeridian, and vertical
+	// the pixel dj rows up or down, or -1 beyond the edge of the region
+	across := func(pixel, di int) int {
+		i, j := pixel%width+di, pixel/width
+		if i < 0 || i >= width {
+			if !region.Wraps() {
+				return -1
+			}
+			i = (i + width) % width
+		}
+		return region.Index(i, j)
+	}
+	vertical := func(pixel, dj int) int {
+		if j := pixel/width + dj; j >= 0 && j < height {
+			return region.Index(pixel%width, j)
+		}
+		return -1
+	}
+	// upwind returns the earliest fixed time of two neighbours
+	upwind := func(a, b int) float64 {
+		t := math.Inf(1)
+		for _, n := range [2]int{a, b} {
+			if n >= 0 && fixed[n] {
+				t = min(t, seconds[n])
+			}
+		}
+		return t
+	}
+	for queue.Len() > 0 {
+		node := heap.Pop(queue).(routeNode)
+		if fixed[node.pixel] {
+			continue
+		}
+		fixed[node.pixel] = true
+
+		for _, pixel := range [4]int{across(node.pixel, -1), across(node.pixel, 1), vertical(node.pixel, -1), vertical(node.pixel, 1)} {
+			if pixel < 0 || fixed[pixel] || math.IsInf(slowness[pixel], 1) {
+				continue
+			}
+			a := upwind(across(pixel, -1), across(pixel, 1))
+			b := upwind(vertical(pixel, -1), vertical(pixel, 1))
+			if t := arrivalTime(a, b, rowDx[pixel/width], dy, slowness[pixel]); t < seconds[pixel] {
+				seconds[pixel] = t
+				heap.Push(queue, routeNode{pixel: pixel, estimate: t})
+			}
+		}
+	}
+
+	raster := NewRaster(PlateCarree{}, region.West(), region.North(), step, step, width, height, "travel-time")
+	for i, t := range seconds {
+		if !math.IsInf(t, 1) {
+			raster.Bands[0][i] = float32(t / 3600)
+		}
+	}
+	return &TravelTimes{Raster: raster, region: region}, nil
+}
+
+// arrivalTime solves the first order upwind discretisation of the eikonal equation at a pixel, given the earliest
+// arrival times of its neighbours along (a) and across (b) its row, their distances and the pixel's slowness.
+func arrivalTime(a, b, dx, dy, slowness float64) float64 {
+	alongRow, acrossRow := a+slowness*dx, b+slowness*dy
+	if math.IsInf(a, 1) || math.IsInf(b, 1) {
+		return min(alongRow, acrossRow)
+	}
+	// (t-a)²/dx² + (t-b)²/dy² = slowness²
+	wx, wy := 1/(dx*dx), 1/(dy*dy)
+	sum := wx*a + wy*b
+	discriminant := sum*sum - (wx+wy)*(wx*a*a+wy*b*b-slowness*slowness)
+	if discriminant < 0 {
+		return min(alongRow, acrossRow)
+	}
+	t := (sum + math.Sqrt(discriminant)) / (wx + wy)
+	if t < max(a, b) {
+		return min(alongRow, acrossRow)
+	}
+	return t
+}
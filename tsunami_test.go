@@ -0,0 +1,69 @@
+package gebco
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTsunamiTravelTimes(t *testing.T) {
+	// 1 degree pixels of 4000 m deep ocean with a wall of land along 20 degrees east from 40 degrees south to 40
+	// degrees north
+	dataset := writeTestDataset(t, 360, 60, func(x, y int) GebcoSample {
+		if x == 200 && y >= 50 && y < 130 {
+			return GebcoSample{Ice: 100, SubIce: 100, Tid: GebcoTypeLand}
+		}
+		return GebcoSample{Ice: -4000, SubIce: -4000, Tid: GebcoTypeMultiBeam}
+	})
+	grid := dataset.Grid()
+	source := LatLng{Lat: 0.5, Lng: 0.5}
+	speed := LongWaveSpeed(4000)
+
+	times, err := TsunamiTravelTimes(grid, TravelTimeOptions{Region: GlobalBounds, Sources: []LatLng{source}, MinDepth: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hours := func(p LatLng) float64 {
+		x, y := grid.Pixel(p)
+		return float64(times.Raster.Bands[0][y*360+x])
+	}
+
+	testCases := []struct {
+		name       string
+		position   LatLng
+		minPercent float64 // the travel time relative to the direct great circle at the wave speed
+		maxPercent float64
+	}{
+		{"source", source, 0, 0},
+		{"along the equator", LatLng{Lat: 0.5, Lng: 10.5}, 99, 101},
+		{"along the meridian", LatLng{Lat: 15.5, Lng: 0.5}, 99, 101},
+		{"diagonally", LatLng{Lat: 10.5, Lng: 10.5}, 99, 110},
+		{"behind the wall", LatLng{Lat: 0.5, Lng: 25.5}, 200, 400},
+		{"across the antimeridian", LatLng{Lat: 0.5, Lng: -170.5}, 99, 101},
+	}
+	for _, tc := range testCases {
+		direct := Distance(source, tc.position) / speed / 3600
+		got := hours(tc.position)
+		if got < direct*tc.minPercent/100-1e-6 || got > direct*tc.maxPercent/100+1e-6 {
+			t.Errorf("%s: expected %.1f-%.1f%% of %.3f hours, got %.3f", tc.name, tc.minPercent, tc.maxPercent, direct, got)
+		}
+	}
+	if got := hours(LatLng{Lat: 0.5, Lng: 20.5}); !math.IsNaN(got) {
+		t.Errorf("expected no travel time on land, got %f", got)
+	}
+
+	// the wave front after an hour is a ring around the source
+	isochrones := times.Isochrones([]float64{1})
+	if len(isochrones) != 1 || !isochrones[0].Closed {
+		t.Fatalf("expected 1 closed isochrone, got %+v", isochrones)
+	}
+	for _, p := range isochrones[0].Points {
+		if d := Distance(source, p) / speed / 3600; d < 0.9 || d > 1.05 {
+			t.Errorf("expected the isochrone an hour from the source, got %v %.3f hours away", p, d)
+		}
+	}
+
+	region := Bounds{West: 30, South: -10, East: 40, North: 10}
+	if _, err := TsunamiTravelTimes(grid, TravelTimeOptions{Region: region, Sources: []LatLng{source}, MinDepth: 10}); err == nil {
+		t.Errorf("expected an error for a source outside the region")
+	}
+}
@@ -0,0 +1,88 @@
+package gebco
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// VolumeOptions configures WaterVolume.
+type VolumeOptions struct {
+	Region   Bounds        // The region to integrate over, expanded to whole pixels of the grid.
+	Polygons []Polygon     // If any, only the pixels of the region whose centres lie inside one of them are integrated.
+	Surface  GebcoDataType // The surface (ice, sub-ice) bounding the water from below.
+	Top      float64       // The elevation in metres of the reference level the water is below, e.g. 0 for sea level.
+	// Bottom is the elevation in metres below which water is left out, e.g. -1000 for the water between two isobaths,
+	// or math.Inf(-1) for all the water down to the surface.
+	Bottom  float64
+	Workers int // The number of goroutines reading tiles, or 0 for one per processor.
+}
+
+// Volume is the water held between two levels above the surface of a region. Depths are measured down from the
+// top level and weighted by area, over the pixels whose surface lies below it.
+type Volume struct {
+	Top float64 `json:"top"`
+	// Bottom is the bottom level, raised to the deepest surface below the top level where that is higher so that it
+	// stays finite when integrating down to the surface.
+	Bottom      float64 `json:"bottom"`
+	Pixels      int     `json:"pixels"`      // The number of pixels below the top level.
+	Area        float64 `json:"area"`        // The area in square metres of the pixels below the top level.
+	Volume      float64 `json:"volume"`      // The volume in cubic metres of the water between the levels.
+	MeanDepth   float64 `json:"meanDepth"`   // The mean depth in metres of the surface below the top level.
+	MedianDepth float64 `json:"medianDepth"` // The depth in metres above which lies half of the area.
+	MaxDepth    float64 `json:"maxDepth"`    // The depth in metres of the deepest pixel.
+}
+
+// WaterVolume integrates the water between two levels over a region, or over the parts of it inside a set of
+// polygons, in a single pass over the tiles of the grid read in parallel. Each pixel below the top level holds the
+// column of water from the top level down to the higher of its surface and the bottom level, over its geodesic area.
+func WaterVolume(grid *Grid, opts VolumeOptions) (*Volume, error) {
+	if opts.Surface == GebcoDataTypeId {
+		return nil, fmt.Errorf("cannot compute the volume above type IDs")
+	}
+	if math.IsNaN(opts.Top) || math.IsNaN(opts.Bottom) || opts.Bottom >= opts.Top {
+		return nil, fmt.Errorf("invalid levels %g to %g: expected the bottom below the top", opts.Bottom, opts.Top)
+	}
+
+	// the surfaces are whole metres, so counting by elevation keeps every depth for the median
+	counts, err := countElevations(grid, opts.Region, opts.Polygons, opts.Surface, opts.Workers, func(elevation float64) (int, bool) {
+		return int(math.Floor(elevation)), elevation < opts.Top
+	})
+	if err != nil {
+		return nil, err
+	}
+	elevations := make([]int, 0, len(counts))
+	for elevation := range counts {
+		elevations = append(elevations, elevation)
+	}
+	slices.SortFunc(elevations, func(a, b int) int {
+		return cmp.Compare(b, a)
+	})
+
+	volume := &Volume{Top: opts.Top, Bottom: opts.Bottom}
+	depthArea := 0.0
+	for _, elevation := range elevations {
+		count := counts[elevation]
+		depth := opts.Top - float64(elevation)
+		volume.Pixels += count.pixels
+		volume.Area += count.area
+		volume.Volume += count.area * (opts.Top - max(float64(elevation), opts.Bottom))
+		volume.MaxDepth = depth
+		depthArea += count.area * depth
+	}
+	volume.Bottom = max(opts.Bottom, opts.Top-volume.MaxDepth)
+	if volume.Area > 0 {
+		volume.MeanDepth = depthArea / volume.Area
+	}
+	// the shallowest depth with at least half of the area above it, counting down from the top level
+	above := 0.0
+	for _, elevation := range elevations {
+		above += counts[elevation].area
+		if above >= volume.Area/2 {
+			volume.MedianDepth = opts.Top - float64(elevation)
+			break
+		}
+	}
+	return volume, nil
+}
@@ -0,0 +1,70 @@
+package gebco
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCellArea(t *testing.T) {
+	// the rows of a globe of 10 degree pixels sum to the area of the sphere, including the polar caps
+	sum := 0.0
+	for y := range 18 {
+		sum += 36 * CellArea(85-10*float64(y), 10)
+	}
+	if sphere := 4 * math.Pi * EarthRadius * EarthRadius; math.Abs(sum/sphere-1) > 1e-12 {
+		t.Errorf("expected the cells to sum to %g square metres, got %g", sphere, sum)
+	}
+	if polar := EarthRadius * EarthRadius * radians(4) * (1 - math.Sin(radians(87))); math.Abs(CellArea(89, 4)/polar-1) > 1e-12 {
+		t.Errorf("expected a cell across the pole clamped to the cap of %g square metres, got %g", polar, CellArea(89, 4))
+	}
+}
+
+func TestWaterVolume(t *testing.T) {
+	// rows of 10 degree pixels falling 500 m at a time from 1000 m in the north
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		z := int16(1000 - 500*y)
+		return GebcoSample{Ice: z, SubIce: z, Tid: GebcoTypeMultiBeam}
+	})
+	grid := dataset.Grid()
+	rowArea := func(y int) float64 {
+		return 36 * CellArea(85-10*float64(y), 10)
+	}
+
+	volume, err := WaterVolume(grid, VolumeOptions{Region: GlobalBounds, Surface: GebcoDataSubIce, Top: 0, Bottom: math.Inf(-1), Workers: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedArea, expectedVolume := 0.0, 0.0
+	for y := 3; y < 18; y++ {
+		expectedArea += rowArea(y)
+		expectedVolume += rowArea(y) * float64(500*(y-2))
+	}
+	if volume.Pixels != 15*36 || math.Abs(volume.Area/expectedArea-1) > 1e-9 || math.Abs(volume.Volume/expectedVolume-1) > 1e-9 {
+		t.Errorf("expected %g cubic metres over %g square metres below sea level, got %+v", expectedVolume, expectedArea, volume)
+	}
+	if math.Abs(volume.MeanDepth-volume.Volume/volume.Area) > 1e-6 || volume.MaxDepth != 7500 || volume.Bottom != -7500 {
+		t.Errorf("unexpected mean or maximum depth %+v", volume)
+	}
+	// the rows are symmetric about the equator, so half of the area below sea level is reached in the first row south
+	// of it
+	if volume.MedianDepth != 3500 {
+		t.Errorf("expected a median depth of 3500 m, got %g", volume.MedianDepth)
+	}
+
+	// between the isobaths the deeper pixels hold a full column, the shallower ones reach down to the seafloor
+	volume, err = WaterVolume(grid, VolumeOptions{Region: GlobalBounds, Surface: GebcoDataSubIce, Top: -1000, Bottom: -2000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedVolume = rowArea(5) * 500
+	for y := 6; y < 18; y++ {
+		expectedVolume += rowArea(y) * 1000
+	}
+	if volume.Pixels != 13*36 || volume.Bottom != -2000 || math.Abs(volume.Volume/expectedVolume-1) > 1e-9 {
+		t.Errorf("expected %g cubic metres between the isobaths, got %+v", expectedVolume, volume)
+	}
+
+	if _, err := WaterVolume(grid, VolumeOptions{Region: GlobalBounds, Surface: GebcoDataSubIce}); err == nil {
+		t.Errorf("expected an error for levels that do not enclose any water")
+	}
+}
@@ -0,0 +1,167 @@
+package gebco
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+)
+
+const (
+	WebTileSize        int     = 256                // The width and height in pixels of a Web Mercator XYZ tile.
+	WebMercatorMaxLat  float64 = 85.0511287798066   // The latitude at which the Web Mercator square world ends.
+	webMercatorExtent  float64 = 20037508.342789244 // Half the width of the Web Mercator world in metres.
+	webMercatorZ0Scale float64 = 559082264.0287178  // The OGC scale denominator of zoom level 0 with 256 pixel tiles.
+	terrainRgbOffset   float64 = -10000             // The elevation of a Terrain-RGB value of zero.
+	terrainRgbStep     float64 = 0.1                // The elevation step of each Terrain-RGB value.
+)
+
+// webMercatorLat returns the latitude of a fraction of the way down the Web Mercator world from the north edge.
+func webMercatorLat(fy float64) float64 {
+	return degrees(math.Atan(math.Sinh(math.Pi * (1 - 2*fy))))
+}
+
+// TileBounds returns the geographic extent of an XYZ tile.
+func TileBounds(z, x, y int) Bounds {
+	tiles := float64(int(1) << z)
+	return Bounds{
+		West:  float64(x)/tiles*360 - 180,
+		East:  float64(x+1)/tiles*360 - 180,
+		North: webMercatorLat(float64(y) / tiles),
+		South: webMercatorLat(float64(y+1) / tiles),
+	}
+}
+
+// TileDegreesPerPixel returns the longitude spacing of the pixels of a tile at a zoom level, for choosing a grid
+// with Dataset.GridFor.
+func TileDegreesPerPixel(z int) float64 {
+	return 360 / float64(WebTileSize<<z)
+}
+
+// ValidTile reports whether the tile coordinates exist at their zoom level.
+func ValidTile(z, x, y int) bool {
+	if z < 0 || z > 30 {
+		return false
+	}
+	tiles := 1 << z
+	return x >= 0 && x < tiles && y >= 0 && y < tiles
+}
+
+// tileProjection places the pixels of an XYZ tile on the globe.
+func tileProjection(z, x, y int) imageProjection {
+	worldPixels := float64(WebTileSize << z)
+	return imageProjection{
+		position: func(i, j float64) LatLng {
+			return LatLng{
+				Lat: webMercatorLat((float64(y*WebTileSize) + j) / worldPixels),
+				Lng: NormalizeLng((float64(x*WebTileSize)+i)/worldPixels*360 - 180),
+			}
+		},
+		spacing: func(j int) (dx, dy float64) {
+			lat := webMercatorLat((float64(y*WebTileSize) + float64(j) + 0.5) / worldPixels)
+			// Web Mercator is conformal, so pixels are square on the ground
+			spacing := 2 * math.Pi * EarthRadius * math.Cos(radians(lat)) / worldPixels
+			return spacing, spacing
+		},
+	}
+}
+
+// RenderTile draws an XYZ Web Mercator tile of a grid.
+func RenderTile(grid *Grid, z, x, y int, opts RenderOptions) (*image.NRGBA, error) {
+	if !ValidTile(z, x, y) {
+		return nil, fmt.Errorf("invalid tile %d/%d/%d", z, x, y)
+	}
+	return renderProjected(grid, WebTileSize, WebTileSize, tileProjection(z, x, y), opts)
+}
+
+// EncodeTerrainRgb encodes an elevation in metres as a Mapbox Terrain-RGB colour.
+func EncodeTerrainRgb(elevation float64) color.NRGBA {
+	value := int(math.Round((elevation - terrainRgbOffset) / terrainRgbStep))
+	value = max(0, min(1<<24-1, value))
+	return color.NRGBA{R: uint8(value >> 16), G: uint8(value >> 8), B: uint8(value), A: 0xff}
+}
+
+// DecodeTerrainRgb decodes a Mapbox Terrain-RGB colour to an elevation in metres.
+func DecodeTerrainRgb(c color.NRGBA) float64 {
+	return terrainRgbOffset + float64(int(c.R)<<16|int(c.G)<<8|int(c.B))*terrainRgbStep
+}
+
+// TerrainRgbTile encodes the elevations of a surface over an XYZ Web Mercator tile as a Mapbox Terrain-RGB tile.
+func TerrainRgbTile(grid *Grid, z, x, y int, surface GebcoDataType) (*image.NRGBA, error) {
+	if !ValidTile(z, x, y) {
+		return nil, fmt.Errorf("invalid tile %d/%d/%d", z, x, y)
+	}
+	projection := tileProjection(z, x, y)
+	img := image.NewNRGBA(image.Rect(0, 0, WebTileSize, WebTileSize))
+	buf := grid.newSampleBuffer()
+	for j := range WebTileSize {
+		for i := range WebTileSize {
+			px, py := grid.Pixel(projection.position(float64(i)+0.5, float64(j)+0.5))
+			sample, err := grid.sampleInto(px, py, buf)
+			if err != nil {
+				return nil, err
+			}
+			img.SetNRGBA(i, j, EncodeTerrainRgb(sample.Value(surface)))
+		}
+	}
+	return img, nil
+}
+
+// WmtsCapabilities returns an OGC WMTS 1.0.0 capabilities document describing a colour relief layer ("gebco")
+// and a Terrain-RGB layer ("gebco-terrain-rgb") served as RESTful XYZ tiles under baseURL, using the
+// GoogleMapsCompatible tile matrix set up to maxZoom.
+func WmtsCapabilities(baseURL string, maxZoom int) string {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	var matrices strings.Builder
+	for z := 0; z <= maxZoom; z++ {
+		fmt.Fprintf(&matrices, `
+      <TileMatrix>
+        <ows:Identifier>%d</ows:Identifier>
+        <ScaleDenominator>%.10f</ScaleDenominator>
+        <TopLeftCorner>%.8f %.8f</TopLeftCorner>
+        <TileWidth>%d</TileWidth>
+        <TileHeight>%d</TileHeight>
+        <MatrixWidth>%d</MatrixWidth>
+        <MatrixHeight>%d</MatrixHeight>
+      </TileMatrix>`, z, webMercatorZ0Scale/float64(int(1)<<z), -webMercatorExtent, webMercatorExtent, WebTileSize, WebTileSize, 1<<z, 1<<z)
+	}
+
+	layer := func(identifier, title, path string) string {
+		return fmt.Sprintf(`
+    <Layer>
+      <ows:Title>%s</ows:Title>
+      <ows:WGS84BoundingBox>
+        <ows:LowerCorner>-180 %.10f</ows:LowerCorner>
+        <ows:UpperCorner>180 %.10f</ows:UpperCorner>
+      </ows:WGS84BoundingBox>
+      <ows:Identifier>%s</ows:Identifier>
+      <Style isDefault="true">
+        <ows:Identifier>default</ows:Identifier>
+      </Style>
+      <Format>image/png</Format>
+      <TileMatrixSetLink>
+        <TileMatrixSet>GoogleMapsCompatible</TileMatrixSet>
+      </TileMatrixSetLink>
+      <ResourceURL format="image/png" resourceType="tile" template="%s/%s/{TileMatrix}/{TileCol}/{TileRow}.png"/>
+    </Layer>`, title, -WebMercatorMaxLat, WebMercatorMaxLat, identifier, baseURL, path)
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<Capabilities xmlns="http://www.opengis.net/wmts/1.0" xmlns:ows="http://www.opengis.net/ows/1.1" xmlns:xlink="http://www.w3.org/1999/xlink" version="1.0.0">
+  <ows:ServiceIdentification>
+    <ows:Title>GEBCO</ows:Title>
+    <ows:ServiceType>OGC WMTS</ows:ServiceType>
+    <ows:ServiceTypeVersion>1.0.0</ows:ServiceTypeVersion>
+  </ows:ServiceIdentification>
+  <Contents>%s%s
+    <TileMatrixSet>
+      <ows:Identifier>GoogleMapsCompatible</ows:Identifier>
+      <ows:SupportedCRS>urn:ogc:def:crs:EPSG::3857</ows:SupportedCRS>
+      <WellKnownScaleSet>urn:ogc:def:wkss:OGC:1.0:GoogleMapsCompatible</WellKnownScaleSet>%s
+    </TileMatrixSet>
+  </Contents>
+  <ServiceMetadataURL xlink:href="%s/wmts/1.0.0/WMTSCapabilities.xml"/>
+</Capabilities>
+`, layer("gebco", "GEBCO colour relief", "tiles"), layer("gebco-terrain-rgb", "GEBCO Terrain-RGB", "terrain"), matrices.String(), baseURL)
+}
@@ -0,0 +1,59 @@
+package gebco
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestTileBounds(t *testing.T) {
+	cases := []struct {
+		name     string
+		z, x, y  int
+		expected Bounds
+	}{
+		{"world", 0, 0, 0, Bounds{West: -180, South: -WebMercatorMaxLat, East: 180, North: WebMercatorMaxLat}},
+		{"south east quadrant", 1, 1, 1, Bounds{West: 0, South: -WebMercatorMaxLat, East: 180, North: 0}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := TileBounds(c.z, c.x, c.y)
+			if math.Abs(actual.West-c.expected.West) > 1e-9 || math.Abs(actual.East-c.expected.East) > 1e-9 ||
+				math.Abs(actual.North-c.expected.North) > 1e-9 || math.Abs(actual.South-c.expected.South) > 1e-9 {
+				t.Errorf("expected %+v, got %+v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTerrainRgbRoundTrip(t *testing.T) {
+	for _, elevation := range []float64{-10000, -10916.3, -4321.7, 0, 8848.8} {
+		decoded := DecodeTerrainRgb(EncodeTerrainRgb(elevation))
+		if math.Abs(decoded-max(elevation, -10000)) > 0.05+1e-9 {
+			t.Errorf("expected %f to round trip, got %f", elevation, decoded)
+		}
+	}
+}
+
+func TestTerrainRgbTileConcurrent(t *testing.T) {
+	dataset := writeTestDataset(t, 36, 6, func(x, y int) GebcoSample {
+		return GebcoSample{Ice: int16(-100 * y), SubIce: int16(-100 * y)}
+	})
+
+	// many concurrent requests share the dataset tile cache
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			img, err := TerrainRgbTile(dataset.Grid(), 1, 0, 1, GebcoDataIce)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			// the top of the south-west tile sits on the equator, pixel row 9 of the grid
+			if elevation := DecodeTerrainRgb(img.NRGBAAt(0, 0)); elevation != -900 {
+				t.Errorf("expected -900 at the top of the tile, got %f", elevation)
+			}
+		})
+	}
+	wg.Wait()
+}
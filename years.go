@@ -0,0 +1,112 @@
+package gebco
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gracefulearth/gopixi"
+)
+
+// YearSample holds the values of a GEBCO cell in one release.
+type YearSample struct {
+	Year int `json:"year"`
+	GebcoSample
+}
+
+// FormatYears formats a list of GEBCO release years as the years tag of a Pixi file with a stacked year layer.
+func FormatYears(years []int) string {
+	parts := make([]string, len(years))
+	for i, year := range years {
+		parts[i] = strconv.Itoa(year)
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseYears parses a comma separated list of GEBCO release years, as written to the years tag of a Pixi file or
+// given on the command line. The years are returned sorted, and must be distinct.
+func ParseYears(text string) ([]int, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	years := []int{}
+	for part := range strings.SplitSeq(text, ",") {
+		year, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid year %q: %w", part, err)
+		}
+		years = append(years, year)
+	}
+	slices.Sort(years)
+	for i := 1; i < len(years); i++ {
+		if years[i] == years[i-1] {
+			return nil, fmt.Errorf("year %d is listed more than once", years[i])
+		}
+	}
+	return years, nil
+}
+
+// yearSlice implements gopixi.TileAccessLayer, presenting one year of a layer stacked along a third year
+// dimension as a layer of its own so that it can be read as a Grid. The year dimension must have a tile size of
+// one, so that every tile of the stacked layer holds a single year.
+type yearSlice struct {
+	stack gopixi.TileAccessLayer
+	layer gopixi.Layer
+	index int // the position of the year along the year dimension
+}
+
+var _ gopixi.TileAccessLayer = (*yearSlice)(nil)
+
+func newYearSlice(stack gopixi.TileAccessLayer, index int, year int) *yearSlice {
+	layer := stack.Layer()
+	layer.Name = fmt.Sprintf("%s_%d", layer.Name, year)
+	layer.Dimensions = layer.Dimensions[:2]
+	return &yearSlice{stack: stack, layer: layer, index: index}
+}
+
+func (s *yearSlice) Layer() gopixi.Layer {
+	return s.layer
+}
+
+func (s *yearSlice) Header() gopixi.Header {
+	return s.stack.Header()
+}
+
+func (s *yearSlice) Tile(tile int) ([]byte, error) {
+	// separated layers number the tiles of each channel after those of the previous one
+	yearTiles := s.layer.Dimensions.Tiles()
+	channel, inYear := tile/yearTiles, tile%yearTiles
+	return s.stack.Tile(channel*s.stack.Layer().Dimensions.Tiles() + s.index*yearTiles + inYear)
+}
+
+// Years returns the GEBCO releases stacked in the dataset's year layer, oldest first, or nil if it has none.
+func (d *Dataset) Years() []int {
+	return d.years
+}
+
+// YearGrid returns the full resolution grid of one release stacked in the dataset's year layer.
+func (d *Dataset) YearGrid(year int) (*Grid, error) {
+	index := slices.Index(d.years, year)
+	if index < 0 {
+		return nil, fmt.Errorf("year %d is not stacked in the dataset (years %v)", year, d.years)
+	}
+	return d.yearGrids[index], nil
+}
+
+// TimeSeries returns the values of the pixel containing a position in every release stacked in the dataset's
+// year layer, oldest first.
+func (d *Dataset) TimeSeries(p LatLng) ([]YearSample, error) {
+	if len(d.years) == 0 {
+		return nil, fmt.Errorf("dataset has no stacked year layer")
+	}
+	series := make([]YearSample, len(d.years))
+	for i, grid := range d.yearGrids {
+		sample, err := grid.SampleAt(p)
+		if err != nil {
+			return nil, err
+		}
+		series[i] = YearSample{Year: d.years[i], GebcoSample: sample}
+	}
+	return series, nil
+}
@@ -0,0 +1,113 @@
+package gebco
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gracefulearth/gopixi"
+)
+
+// yearsTestLayer makes a gebco_years layer for writeTestDataset stacking every year, with the years tag listing
+// them.
+func yearsTestLayer(width int, tileSize int, years []int, opts []gopixi.LayerOption, value func(x, y, year int) GebcoSample) testLayer {
+	return testLayer{
+		layer: gopixi.NewLayer("gebco_years",
+			gopixi.DimensionSet{
+				{Name: "lng", TileSize: tileSize, Size: width},
+				{Name: "lat", TileSize: tileSize, Size: width / 2},
+				{Name: "year", TileSize: 1, Size: len(years)}},
+			gopixi.ChannelSet{
+				{Name: "ice", Type: gopixi.ChannelInt16},
+				{Name: "sub-ice", Type: gopixi.ChannelInt16},
+				{Name: "tid", Type: gopixi.ChannelUint8}},
+			opts...),
+		sample: func(coord gopixi.SampleCoordinate) gopixi.Sample {
+			sample := value(coord[0], coord[1], years[coord[2]])
+			return gopixi.Sample{sample.Ice, sample.SubIce, uint8(sample.Tid)}
+		},
+		tags: map[string]string{"years": FormatYears(years)},
+	}
+}
+
+func TestParseYears(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected []int
+		fails    bool
+	}{
+		{"", nil, false},
+		{"2025", []int{2025}, false},
+		{"2024, 2021,2023", []int{2021, 2023, 2024}, false},
+		{"2021,2021", nil, true},
+		{"2021,next", nil, true},
+	}
+	for _, c := range cases {
+		t.Run(c.text, func(t *testing.T) {
+			actual, err := ParseYears(c.text)
+			if (err != nil) != c.fails {
+				t.Fatalf("expected failure %v, got error %v", c.fails, err)
+			}
+			if !slices.Equal(actual, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDatasetTimeSeries(t *testing.T) {
+	years := []int{2021, 2023, 2025}
+	layouts := []struct {
+		name string
+		opts []gopixi.LayerOption
+	}{
+		{"interleaved", []gopixi.LayerOption{gopixi.WithCompression(gopixi.CompressionFlate)}},
+		{"planar", []gopixi.LayerOption{gopixi.WithCompression(gopixi.CompressionFlate), gopixi.WithPlanar()}},
+	}
+	for _, layout := range layouts {
+		t.Run(layout.name, func(t *testing.T) {
+			value := func(x, y, year int) GebcoSample {
+				tid := GebcoTypeInterpolated
+				if year >= 2023 {
+					tid = GebcoTypeMultiBeam
+				}
+				return GebcoSample{Ice: int16(-100*x - (year - 2020)), SubIce: int16(-y), Tid: tid}
+			}
+			latest := func(x, y int) GebcoSample { return value(x, y, 2025) }
+			dataset := writeTestDataset(t, 36, 6, latest, yearsTestLayer(36, 6, years, layout.opts, value))
+			if !slices.Equal(dataset.Years(), years) {
+				t.Fatalf("expected years %v, got %v", years, dataset.Years())
+			}
+			if dataset.Grid().Name() != "gebco" {
+				t.Errorf("expected the unstacked layer as the finest grid, got %s", dataset.Grid().Name())
+			}
+
+			series, err := dataset.TimeSeries(LatLng{Lat: 0.5, Lng: -145})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := []YearSample{
+				{Year: 2021, GebcoSample: GebcoSample{Ice: -301, SubIce: -8, Tid: GebcoTypeInterpolated}},
+				{Year: 2023, GebcoSample: GebcoSample{Ice: -303, SubIce: -8, Tid: GebcoTypeMultiBeam}},
+				{Year: 2025, GebcoSample: GebcoSample{Ice: -305, SubIce: -8, Tid: GebcoTypeMultiBeam}},
+			}
+			if !slices.Equal(series, expected) {
+				t.Errorf("expected %+v, got %+v", expected, series)
+			}
+
+			grid, err := dataset.YearGrid(2023)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sample, err := grid.Sample(35, 17)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sample != (GebcoSample{Ice: -3503, SubIce: -17, Tid: GebcoTypeMultiBeam}) {
+				t.Errorf("unexpected 2023 sample %+v", sample)
+			}
+			if _, err := dataset.YearGrid(2022); err == nil {
+				t.Errorf("expected an error for a year that is not stacked")
+			}
+		})
+	}
+}